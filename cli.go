@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go_test/service"
+	"log"
+	"os"
+)
+
+const cliUsage = `用法:
+  go run . import -file posts.zip -author 1 [-dry-run]   导入Markdown压缩包
+  go run . export -file articles.zip [-status published] 导出文章为Markdown压缩包`
+
+// isCommand 是否为已知的命令行子命令，其他参数不影响服务启动
+func isCommand(name string) bool {
+	switch name {
+	case "import", "export":
+		return true
+	}
+	return false
+}
+
+// runCommand 执行命令行子命令
+func runCommand(args []string) {
+	switch args[0] {
+	case "import":
+		runImport(args[1:])
+	case "export":
+		runExport(args[1:])
+	default:
+		fmt.Println(cliUsage)
+		os.Exit(2)
+	}
+}
+
+// runImport 从zip导入文章，输出导入报告
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "zip文件路径")
+	authorID := fs.Uint("author", 0, "导入人用户ID，front matter中的作者不存在时作为文章作者")
+	dryRun := fs.Bool("dry-run", false, "只检查冲突，不写入数据库")
+	_ = fs.Parse(args)
+	if *file == "" || *authorID == 0 {
+		fmt.Println(cliUsage)
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("打开文件失败: %v", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		log.Fatalf("读取文件失败: %v", err)
+	}
+
+	report, err := service.NewArticleService().ImportArticles(f, info.Size(), *authorID, *dryRun)
+	if err != nil {
+		log.Fatalf("导入失败: %v", err)
+	}
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+}
+
+// runExport 导出文章到zip文件
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "articles.zip", "输出zip文件路径")
+	status := fs.String("status", "", "只导出指定状态的文章，为空时导出全部")
+	_ = fs.Parse(args)
+
+	f, err := os.Create(*file)
+	if err != nil {
+		log.Fatalf("创建文件失败: %v", err)
+	}
+	defer f.Close()
+
+	total, err := service.NewArticleService().ExportArticles(f, *status)
+	if err != nil {
+		log.Fatalf("导出失败: %v", err)
+	}
+	fmt.Printf("已导出%d篇文章到%s\n", total, *file)
+}
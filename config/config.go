@@ -9,15 +9,28 @@ import (
 )
 
 var (
-	appConfig   atomic.Value // *Config
-	dbConfig    atomic.Value // *DBConfig
-	cacheConfig atomic.Value // *CacheConfig
-	jwtConfig   atomic.Value // *JWTConfig
+	appConfig       atomic.Value // *Config
+	dbConfig        atomic.Value // *DBConfig
+	cacheConfig     atomic.Value // *CacheConfig
+	jwtConfig       atomic.Value // *JWTConfig
+	trashConfig     atomic.Value // *TrashConfig
+	schedulerConfig atomic.Value // *SchedulerConfig
+	markdownConfig  atomic.Value // *MarkdownConfig
+	commentConfig   atomic.Value // *CommentConfig
+	likeConfig      atomic.Value // *LikeConfig
+	viewConfig      atomic.Value // *ViewConfig
+	searchConfig    atomic.Value // *SearchConfig
+	siteConfig      atomic.Value // *SiteConfig
+	loginConfig     atomic.Value // *LoginConfig
+	twoFactorConfig atomic.Value // *TwoFactorConfig
+	mailConfig      atomic.Value // *MailConfig
+	accountConfig   atomic.Value // *AccountConfig
 )
 
 type Config struct {
-	Name string `mapstructure:"name"`
-	Port int    `mapstructure:"port"`
+	Name           string   `mapstructure:"name"`
+	Port           int      `mapstructure:"port"`
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // 可信的反向代理地址（IP或CIDR），只有来自这些地址的 X-Forwarded-For 才会被采信
 }
 
 type DBConfig struct {
@@ -37,8 +50,98 @@ type CacheConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
+	Secret              string `mapstructure:"secret"`
+	ExpireHours         int    `mapstructure:"expire_hours"`          // 已废弃，未配置access_expire_minutes时作为访问令牌有效期
+	AccessExpireMinutes int    `mapstructure:"access_expire_minutes"` // 访问令牌有效期（分钟）
+	RefreshExpireHours  int    `mapstructure:"refresh_expire_hours"`  // 刷新令牌有效期（小时）
+}
+
+type TrashConfig struct {
+	RetentionDays        int `mapstructure:"retention_days"`         // 回收站保留天数，<=0 表示不自动清理
+	PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"` // 自动清理任务执行间隔（分钟）
+}
+
+type SchedulerConfig struct {
+	IntervalSeconds int `mapstructure:"interval_seconds"` // 定时发布检查间隔（秒）
+	LockTTLSeconds  int `mapstructure:"lock_ttl_seconds"` // 分布式锁过期时间（秒）
+}
+
+type MarkdownConfig struct {
+	AllowedTags       []string            `mapstructure:"allowed_tags"`        // 允许保留的HTML标签
+	AllowedAttrs      map[string][]string `mapstructure:"allowed_attrs"`       // 标签 -> 允许的属性
+	AllowedURLSchemes []string            `mapstructure:"allowed_url_schemes"` // 链接允许的协议
+}
+
+type CommentConfig struct {
+	RequireApproval bool `mapstructure:"require_approval"` // 新评论是否需要管理员审核后才公开
+}
+
+type LikeConfig struct {
+	FlushIntervalSeconds int `mapstructure:"flush_interval_seconds"` // 点赞数据回写MySQL的间隔（秒）
+	BatchSize            int `mapstructure:"batch_size"`             // 每批回写的文章数量
+}
+
+type ViewConfig struct {
+	FlushIntervalSeconds int `mapstructure:"flush_interval_seconds"` // 浏览统计持久化到MySQL的间隔（秒）
+}
+
+type SearchConfig struct {
+	Engine        string  `mapstructure:"engine"`         // 搜索引擎：mysql（FULLTEXT + ngram）或 memory（进程内倒排索引）
+	TitleWeight   float64 `mapstructure:"title_weight"`   // 标题命中的权重
+	ContentWeight float64 `mapstructure:"content_weight"` // 正文命中的权重
+	MaxResults    int     `mapstructure:"max_results"`    // 单次搜索最多返回的命中数
+	Highlight     string  `mapstructure:"highlight"`      // 默认高亮标记：mark（<mark>标签）或 markdown（**）
+	SnippetRadius int     `mapstructure:"snippet_radius"` // 摘要中命中位置前后保留的字符数
+	MaxSnippets   int     `mapstructure:"max_snippets"`   // 摘要最多包含的片段数
+}
+
+type SiteConfig struct {
+	Title       string `mapstructure:"title"`        // 站点名称，用于订阅源标题
+	Description string `mapstructure:"description"`  // 站点简介
+	BaseURL     string `mapstructure:"base_url"`     // 站点对外访问地址，用于生成文章和订阅源的绝对链接
+	Language    string `mapstructure:"language"`     // 站点语言，如 zh-CN
+	FeedSize    int    `mapstructure:"feed_size"`    // 订阅源包含的最新文章数量
+	ArticleURL  string `mapstructure:"article_url"`  // 前端文章页地址模板，支持{slug}和{id}，为空时指向公开的文章接口
+	HomeURL     string `mapstructure:"home_url"`     // 前端首页地址，为空时指向公开的文章列表接口
+	TagURL      string `mapstructure:"tag_url"`      // 前端标签页地址模板，支持{name}，为空时指向按标签筛选的分页接口
+	CategoryURL string `mapstructure:"category_url"` // 前端分类页地址模板，支持{name}，为空时指向按分类筛选的分页接口
+}
+
+type LoginConfig struct {
+	MaxAttempts        int `mapstructure:"max_attempts"`         // 同一用户名连续失败多少次后临时锁定
+	IPMaxAttempts      int `mapstructure:"ip_max_attempts"`      // 同一IP失败多少次后临时封禁
+	WindowMinutes      int `mapstructure:"window_minutes"`       // 失败次数的统计窗口（分钟）
+	LockMinutes        int `mapstructure:"lock_minutes"`         // 锁定时长（分钟）
+	BackoffBaseSeconds int `mapstructure:"backoff_base_seconds"` // 失败后的等待时间基数，每多失败一次翻倍
+	BackoffMaxSeconds  int `mapstructure:"backoff_max_seconds"`  // 等待时间上限
+}
+
+type TwoFactorConfig struct {
+	Issuer           string `mapstructure:"issuer"`            // 验证器App中显示的服务名称
+	RequireForAdmin  bool   `mapstructure:"require_for_admin"` // 为true时管理员必须启用两步验证才能登录
+	ChallengeMinutes int    `mapstructure:"challenge_minutes"` // 登录第二步的有效期（分钟）
+	EncryptionKey    string `mapstructure:"encryption_key"`    // 加密保存TOTP密钥的密钥，与JWT密钥分开，更换后已绑定的两步验证全部失效
+}
+
+type MailConfig struct {
+	Driver   string `mapstructure:"driver"`   // smtp：通过SMTP发送（默认）；log：打印到日志（本地开发）
+	Host     string `mapstructure:"host"`     // SMTP服务器地址
+	Port     int    `mapstructure:"port"`     // SMTP端口，465使用TLS直连，其余端口在服务器支持时使用STARTTLS
+	Username string `mapstructure:"username"` // SMTP用户名，为空时不认证
+	Password string `mapstructure:"password"` // SMTP密码
+	From     string `mapstructure:"from"`     // 发件人地址
+	Dir      string `mapstructure:"dir"`      // log驱动下同时把完整邮件写入该目录，日志中的令牌会隐藏
+}
+
+type AccountConfig struct {
+	VerifyEmailExpireHours     int    `mapstructure:"verify_email_expire_hours"`     // 邮箱验证链接有效期（小时）
+	ResetPasswordExpireMinutes int    `mapstructure:"reset_password_expire_minutes"` // 重置密码链接有效期（分钟）
+	ResetPasswordURL           string `mapstructure:"reset_password_url"`            // 重置密码页面地址，为空时使用 site.base_url + /reset-password
+	RateLimitWindowMinutes     int    `mapstructure:"rate_limit_window_minutes"`     // 限流统计窗口（分钟）
+	ForgotPasswordPerIP        int    `mapstructure:"forgot_password_per_ip"`        // 窗口内同一IP最多申请重置密码的次数
+	ForgotPasswordPerEmail     int    `mapstructure:"forgot_password_per_email"`     // 窗口内同一邮箱最多发送重置邮件的次数
+	ResetPasswordPerIP         int    `mapstructure:"reset_password_per_ip"`         // 窗口内同一IP最多提交重置密码的次数
+	VerifyEmailPerUser         int    `mapstructure:"verify_email_per_user"`         // 窗口内同一用户最多发送验证邮件的次数
 }
 
 // GetAppConfig 原子读取应用配置
@@ -73,6 +176,102 @@ func GetJWTConfig() *JWTConfig {
 	return nil
 }
 
+// GetTrashConfig 原子读取回收站配置
+func GetTrashConfig() *TrashConfig {
+	if config := trashConfig.Load(); config != nil {
+		return config.(*TrashConfig)
+	}
+	return nil
+}
+
+// GetSchedulerConfig 原子读取定时发布配置
+func GetSchedulerConfig() *SchedulerConfig {
+	if config := schedulerConfig.Load(); config != nil {
+		return config.(*SchedulerConfig)
+	}
+	return nil
+}
+
+// GetMarkdownConfig 原子读取Markdown渲染配置
+func GetMarkdownConfig() *MarkdownConfig {
+	if config := markdownConfig.Load(); config != nil {
+		return config.(*MarkdownConfig)
+	}
+	return nil
+}
+
+// GetCommentConfig 原子读取评论配置
+func GetCommentConfig() *CommentConfig {
+	if config := commentConfig.Load(); config != nil {
+		return config.(*CommentConfig)
+	}
+	return nil
+}
+
+// GetLikeConfig 原子读取点赞配置
+func GetLikeConfig() *LikeConfig {
+	if config := likeConfig.Load(); config != nil {
+		return config.(*LikeConfig)
+	}
+	return nil
+}
+
+// GetViewConfig 原子读取浏览统计配置
+func GetViewConfig() *ViewConfig {
+	if config := viewConfig.Load(); config != nil {
+		return config.(*ViewConfig)
+	}
+	return nil
+}
+
+// GetSearchConfig 原子读取搜索配置
+func GetSearchConfig() *SearchConfig {
+	if config := searchConfig.Load(); config != nil {
+		return config.(*SearchConfig)
+	}
+	return nil
+}
+
+// GetSiteConfig 原子读取站点配置
+func GetSiteConfig() *SiteConfig {
+	if config := siteConfig.Load(); config != nil {
+		return config.(*SiteConfig)
+	}
+	return nil
+}
+
+// GetLoginConfig 原子读取登录保护配置
+func GetLoginConfig() *LoginConfig {
+	if config := loginConfig.Load(); config != nil {
+		return config.(*LoginConfig)
+	}
+	return nil
+}
+
+// GetTwoFactorConfig 原子读取两步验证配置
+func GetTwoFactorConfig() *TwoFactorConfig {
+	if config := twoFactorConfig.Load(); config != nil {
+		return config.(*TwoFactorConfig)
+	}
+	return nil
+}
+
+// GetMailConfig 原子读取邮件配置
+func GetMailConfig() *MailConfig {
+	if config := mailConfig.Load(); config != nil {
+		return config.(*MailConfig)
+	}
+	return nil
+}
+
+// GetAccountConfig 原子读取账户安全配置
+func GetAccountConfig() *AccountConfig {
+	if config := accountConfig.Load(); config != nil {
+		return config.(*AccountConfig)
+	}
+	return nil
+}
+
 func InitConfig() {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yml")
@@ -107,6 +306,78 @@ func InitConfig() {
 	}
 	jwtConfig.Store(jwt)
 
+	trash := &TrashConfig{}
+	if err := viper.UnmarshalKey("trash", trash); err != nil {
+		log.Fatalf("解析回收站配置失败: %v", err)
+	}
+	trashConfig.Store(trash)
+
+	scheduler := &SchedulerConfig{}
+	if err := viper.UnmarshalKey("scheduler", scheduler); err != nil {
+		log.Fatalf("解析定时发布配置失败: %v", err)
+	}
+	schedulerConfig.Store(scheduler)
+
+	markdown := &MarkdownConfig{}
+	if err := viper.UnmarshalKey("markdown", markdown); err != nil {
+		log.Fatalf("解析Markdown配置失败: %v", err)
+	}
+	markdownConfig.Store(markdown)
+
+	comment := &CommentConfig{}
+	if err := viper.UnmarshalKey("comment", comment); err != nil {
+		log.Fatalf("解析评论配置失败: %v", err)
+	}
+	commentConfig.Store(comment)
+
+	like := &LikeConfig{}
+	if err := viper.UnmarshalKey("like", like); err != nil {
+		log.Fatalf("解析点赞配置失败: %v", err)
+	}
+	likeConfig.Store(like)
+
+	view := &ViewConfig{}
+	if err := viper.UnmarshalKey("view", view); err != nil {
+		log.Fatalf("解析浏览统计配置失败: %v", err)
+	}
+	viewConfig.Store(view)
+
+	search := &SearchConfig{}
+	if err := viper.UnmarshalKey("search", search); err != nil {
+		log.Fatalf("解析搜索配置失败: %v", err)
+	}
+	searchConfig.Store(search)
+
+	site := &SiteConfig{}
+	if err := viper.UnmarshalKey("site", site); err != nil {
+		log.Fatalf("解析站点配置失败: %v", err)
+	}
+	siteConfig.Store(site)
+
+	login := &LoginConfig{}
+	if err := viper.UnmarshalKey("login", login); err != nil {
+		log.Fatalf("解析登录保护配置失败: %v", err)
+	}
+	loginConfig.Store(login)
+
+	twoFactor := &TwoFactorConfig{}
+	if err := viper.UnmarshalKey("two_factor", twoFactor); err != nil {
+		log.Fatalf("解析两步验证配置失败: %v", err)
+	}
+	twoFactorConfig.Store(twoFactor)
+
+	mail := &MailConfig{}
+	if err := viper.UnmarshalKey("mail", mail); err != nil {
+		log.Fatalf("解析邮件配置失败: %v", err)
+	}
+	mailConfig.Store(mail)
+
+	account := &AccountConfig{}
+	if err := viper.UnmarshalKey("account", account); err != nil {
+		log.Fatalf("解析账户安全配置失败: %v", err)
+	}
+	accountConfig.Store(account)
+
 	global.InitDB(InitDB())
 	global.InitRedis(InitRedis())
 }
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var accountService = service.NewAccountService()
+
+// ForgotPassword 申请重置密码，无论邮箱是否注册都返回相同结果
+func ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	if err := accountService.ForgotPassword(req.Email, c.ClientIP()); err != nil {
+		if err.Error() == "请求过于频繁，请稍后再试" {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		} else if err.Error() == "邮件服务未配置，请联系管理员" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "如果该邮箱已注册，重置密码邮件已发送，请查收"})
+}
+
+// ResetPassword 使用邮件中的令牌设置新密码
+func ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := accountService.ResetPassword(req, c.ClientIP()); err != nil {
+		if err.Error() == "请求过于频繁，请稍后再试" {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		} else if err.Error() == "链接无效或已过期" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "用户账户已被禁用" {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "密码已重置，请使用新密码登录"})
+}
+
+// VerifyEmail 验证邮箱，令牌可放在查询参数（邮件链接）或JSON请求体中
+func VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	if err := accountService.VerifyEmail(req.Token); err != nil {
+		if err.Error() == "链接无效或已过期" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "邮箱验证成功"})
+}
+
+// SendVerificationEmail 重新发送邮箱验证邮件
+func SendVerificationEmail(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := accountService.SendVerificationEmail(uid); err != nil {
+		if err.Error() == "发送过于频繁，请稍后再试" {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		} else if err.Error() == "请先设置邮箱" || err.Error() == "邮箱已验证" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "用户不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "邮件服务未配置，请联系管理员" {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "验证邮件已发送，请查收"})
+}
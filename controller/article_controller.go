@@ -2,10 +2,13 @@ package controller
 
 import (
 	"go_test/dto"
+	"go_test/global"
 	"go_test/service"
 	"go_test/utils"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,15 +17,25 @@ var articleService = service.NewArticleService()
 
 // 创建文章
 func CreateArticle(ctx *gin.Context) {
+	// 从JWT中间件获取作者ID
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
 	var req dto.ArticleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	article, err := articleService.CreateArticle(req)
+	article, err := articleService.CreateArticle(req, uid)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err.Error() == "分类不存在" || strings.HasPrefix(err.Error(), "slug格式无效") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -40,15 +53,19 @@ func GetArticles(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, articles)
 }
 
-// 分页查询文章 - 支持可选关键词搜索（带Redis缓存）
+// 分页查询文章 - 支持可选关键词搜索和标签/分类筛选（带Redis缓存）
 func GetArticlesWithPagination(ctx *gin.Context) {
 	// 使用分页工具从上下文解析分页参数
 	paginate := utils.PaginateFromContext(ctx)
 
-	// 获取可选的关键词参数
-	keyword := ctx.Query("keyword")
+	// 获取可选的关键词、标签和分类筛选参数
+	var filter dto.ArticleFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
 
-	response, err := articleService.GetArticlesWithPagination(paginate, keyword)
+	response, err := articleService.GetArticlesWithPagination(paginate, filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -96,15 +113,150 @@ func BatchDeleteArticles(ctx *gin.Context) {
 func GetArticleByID(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	article, err := articleService.GetArticleByID(id)
+	// 公开接口允许匿名访问，此时只能看到已发布的文章
+	uid, role := getOptionalUser(ctx)
+
+	article, err := articleService.GetArticleByID(id, uid, role == global.RoleAdmin, ctx.ClientIP())
+	if err != nil {
+		if err.Error() == "未找到该文章" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.Header("ETag", utils.VersionETag(article.Version))
+	ctx.JSON(http.StatusOK, article)
+}
+
+// 更新文章（PUT/PATCH均为部分更新，需携带If-Match版本校验）
+func UpdateArticle(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	// 必须携带If-Match头，防止覆盖他人的修改
+	ifMatch := ctx.GetHeader("If-Match")
+	if ifMatch == "" {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "缺少If-Match请求头，请先获取文章的ETag"})
+		return
+	}
+
+	version, ok := utils.ParseVersionETag(ifMatch)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "If-Match格式错误"})
+		return
+	}
+
+	var req dto.ArticleUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	article, err := articleService.UpdateArticle(uint(id), req, version, uid, role == global.RoleAdmin)
 	if err != nil {
 		if err.Error() == "未找到该文章" {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "权限不足，只能编辑自己的文章" {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if err.Error() == "文章已被其他人修改，请刷新后重试" {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		} else if err.Error() == "没有需要更新的字段" || err.Error() == "分类不存在" || strings.HasPrefix(err.Error(), "slug格式无效") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		} else {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
 		return
 	}
 
+	ctx.Header("ETag", utils.VersionETag(article.Version))
 	ctx.JSON(http.StatusOK, article)
 }
+
+// 分页查询指定作者的文章
+func GetArticlesByAuthor(ctx *gin.Context) {
+	authorID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "用户ID格式错误"})
+		return
+	}
+
+	// 公开接口允许匿名访问，此时只能看到已发布的文章
+	uid, role := getOptionalUser(ctx)
+
+	paginate := utils.PaginateFromContext(ctx)
+
+	response, err := articleService.GetArticlesByAuthor(uint(authorID), paginate, uid, role == global.RoleAdmin)
+	if err != nil {
+		if err.Error() == "用户不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// 根据slug获取文章，历史slug返回301重定向到当前slug
+func GetArticleBySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+
+	// 公开接口允许匿名访问，此时只能看到已发布的文章
+	uid, role := getOptionalUser(ctx)
+
+	article, redirectSlug, err := articleService.GetArticleBySlug(slug, uid, role == global.RoleAdmin, ctx.ClientIP())
+	if err != nil {
+		if err.Error() == "未找到该文章" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if redirectSlug != "" {
+		location := strings.TrimSuffix(ctx.Request.URL.Path, slug) + url.PathEscape(redirectSlug)
+		ctx.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	ctx.Header("ETag", utils.VersionETag(article.Version))
+	ctx.JSON(http.StatusOK, article)
+}
+
+// 获取排行榜文章（?window=24h|7d|30d 为热度榜，all 为累计点赞榜）
+func GetTrendingArticles(ctx *gin.Context) {
+	window := ctx.DefaultQuery("window", "24h")
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "limit参数格式错误"})
+		return
+	}
+
+	articles, err := articleService.GetTrendingArticles(window, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "时间窗口参数无效") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"window": window,
+		"data":   articles,
+		"total":  len(articles),
+	})
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"go_test/global"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportUploadSize = 64 << 20 // 导入压缩包最大64MB
+
+// 导入Markdown压缩包（?dry_run=true 只返回冲突报告，不写入数据库）
+func ImportArticles(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "请上传zip文件（字段名file）"})
+		return
+	}
+	if fileHeader.Size > maxImportUploadSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("文件不能超过%dMB", maxImportUploadSize>>20)})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "读取上传文件失败: " + err.Error()})
+		return
+	}
+	defer file.Close()
+
+	report, err := articleService.ImportArticles(file, fileHeader.Size, uid, dryRun)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "无效的zip文件") || strings.HasPrefix(err.Error(), "压缩包内文件过多") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if strings.HasPrefix(err.Error(), "压缩包解压后超过") {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// 导出文章为Markdown压缩包（?status=published 只导出指定状态）
+func ExportArticles(ctx *gin.Context) {
+	status := ctx.Query("status")
+	switch status {
+	case "", global.ArticleStatusDraft, global.ArticleStatusInReview, global.ArticleStatusScheduled,
+		global.ArticleStatusPublished, global.ArticleStatusArchived:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的文章状态"})
+		return
+	}
+
+	// 边查询边写入响应，不在内存中缓存整个压缩包
+	filename := fmt.Sprintf("articles-%s.zip", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if _, err := articleService.ExportArticles(ctx.Writer, status); err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Type")
+			ctx.Writer.Header().Del("Content-Disposition")
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// 已开始传输，无法再返回错误响应，直接断开连接，避免客户端把不完整的文件当作下载成功
+		log.Printf("导出文章失败: %v", err)
+		if conn, _, hijackErr := ctx.Writer.Hijack(); hijackErr == nil {
+			conn.Close()
+		}
+	}
+}
@@ -3,34 +3,107 @@ package controller
 import (
 	"go_test/service"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 var articleLikeService = service.NewArticleLikeService()
 
-// LikeArticle 给文章点赞
+// LikeArticle 给文章点赞（幂等）
 func LikeArticle(ctx *gin.Context) {
-	articleID := ctx.Param("id")
+	articleID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
 
-	err := articleLikeService.LikeArticle(articleID)
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	likes, err := articleLikeService.LikeArticle(uint(articleID), uid)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondLikeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully liked the article"})
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":     "Successfully liked the article",
+		"likes":       likes,
+		"liked_by_me": true,
+	})
 }
 
-// GetArticleLikes 获取文章点赞数量
+// UnlikeArticle 取消点赞（幂等）
+func UnlikeArticle(ctx *gin.Context) {
+	articleID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	likes, err := articleLikeService.UnlikeArticle(uint(articleID), uid)
+	if err != nil {
+		respondLikeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":     "Successfully unliked the article",
+		"likes":       likes,
+		"liked_by_me": false,
+	})
+}
+
+// GetArticleLikes 获取文章点赞数量以及当前用户是否已点赞
 func GetArticleLikes(ctx *gin.Context) {
-	articleID := ctx.Param("id")
+	articleID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
 
-	likes, err := articleLikeService.GetArticleLikes(articleID)
+	// 匿名访问时liked_by_me始终为false
+	uid, _ := getOptionalUser(ctx)
+
+	likes, likedByMe, err := articleLikeService.GetArticleLikes(uint(articleID), uid)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondLikeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"likes": likes})
+	ctx.JSON(http.StatusOK, gin.H{
+		"likes":       likes,
+		"liked_by_me": likedByMe,
+	})
+}
+
+// ReconcileLikes 对比并修复Redis与MySQL中的点赞数据，返回差异报告
+func ReconcileLikes(ctx *gin.Context) {
+	report, err := articleLikeService.ReconcileLikes(ctx.Request.Context())
+	if err != nil {
+		if err.Error() == "点赞数据正在重建，请稍后重试" {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func respondLikeError(ctx *gin.Context, err error) {
+	if err.Error() == "未找到该文章" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
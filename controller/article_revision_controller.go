@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"go_test/global"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 获取文章修订历史
+func GetArticleRevisions(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	revisions, err := articleService.GetArticleRevisions(uint(id), uid, role == global.RoleAdmin)
+	if err != nil {
+		respondRevisionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  revisions,
+		"total": len(revisions),
+	})
+}
+
+// 获取指定版本的修订快照
+func GetArticleRevision(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	version, err := strconv.ParseUint(ctx.Param("version"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "版本号格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	revision, err := articleService.GetArticleRevision(uint(id), uint(version), uid, role == global.RoleAdmin)
+	if err != nil {
+		respondRevisionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, revision)
+}
+
+// 对比两个版本的差异 ?from=1&to=3
+func DiffArticleRevisions(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	from, fromErr := strconv.ParseUint(ctx.Query("from"), 10, 32)
+	to, toErr := strconv.ParseUint(ctx.Query("to"), 10, 32)
+	if fromErr != nil || toErr != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "版本号格式错误，需要提供from和to参数"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	diff, err := articleService.DiffArticleRevisions(uint(id), uint(from), uint(to), uid, role == global.RoleAdmin)
+	if err != nil {
+		respondRevisionError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// 回滚文章到指定版本（生成新版本）
+func RollbackArticle(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	version, err := strconv.ParseUint(ctx.Param("version"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "版本号格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	article, err := articleService.RollbackArticle(uint(id), uint(version), uid, role == global.RoleAdmin)
+	if err != nil {
+		if err.Error() == "该版本已是当前版本" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "文章已被其他人修改，请刷新后重试" {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			respondRevisionError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, article)
+}
+
+// respondRevisionError 修订相关接口的通用错误响应
+func respondRevisionError(ctx *gin.Context, err error) {
+	if err.Error() == "未找到该文章" || err.Error() == "未找到该版本" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if strings.HasPrefix(err.Error(), "权限不足") {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	} else {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"go_test/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var articleViewService = service.NewArticleViewService()
+
+// 查询文章每日浏览统计（?from=2006-01-02&to=2006-01-02，默认最近30天）
+func GetArticleStats(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to, err := parseStatsDate(ctx.Query("to"), today)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "to参数格式错误，应为YYYY-MM-DD"})
+		return
+	}
+	from, err := parseStatsDate(ctx.Query("from"), to.AddDate(0, 0, -29))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "from参数格式错误，应为YYYY-MM-DD"})
+		return
+	}
+
+	stats, err := articleViewService.GetArticleStats(uint(id), from, to)
+	if err != nil {
+		if err.Error() == "未找到该文章" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "结束日期不能早于开始日期" || strings.HasPrefix(err.Error(), "查询范围不能超过") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// parseStatsDate 解析YYYY-MM-DD格式的日期，为空时返回默认值
+func parseStatsDate(value string, defaultValue time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
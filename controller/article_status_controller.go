@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/global"
+	"go_test/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 文章状态流转（提交审核、撤回、定时发布、发布、归档）
+func TransitionArticle(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.ArticleTransitionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	article, err := articleService.TransitionArticle(uint(id), req, uid, role == global.RoleAdmin)
+	if err != nil {
+		if err.Error() == "未找到该文章" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.HasPrefix(err.Error(), "权限不足") {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if strings.HasPrefix(err.Error(), "不允许从") || err.Error() == "文章状态已变更，请刷新后重试" {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else if err.Error() == "定时发布时间必须晚于当前时间" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, article)
+}
+
+// 获取文章状态流转记录
+func GetArticleStatusLogs(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	logs, err := articleService.GetArticleStatusLogs(uint(id), uid, role == global.RoleAdmin)
+	if err != nil {
+		if err.Error() == "未找到该文章" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if strings.HasPrefix(err.Error(), "权限不足") {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  logs,
+		"total": len(logs),
+	})
+}
+
+// 按状态分页查询文章（如审核队列 ?status=in_review）
+func GetArticlesByStatus(ctx *gin.Context) {
+	status := ctx.DefaultQuery("status", global.ArticleStatusInReview)
+	paginate := utils.PaginateFromContext(ctx)
+
+	response, err := articleService.GetArticlesByStatus(status, paginate)
+	if err != nil {
+		if err.Error() == "文章状态参数无效" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"go_test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 分页查询回收站文章
+func GetTrashedArticles(ctx *gin.Context) {
+	paginate := utils.PaginateFromContext(ctx)
+
+	response, err := articleService.GetTrashedArticles(paginate)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// 从回收站恢复文章
+func RestoreArticles(ctx *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := articleService.RestoreArticles(req.IDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":        "成功恢复 " + strconv.Itoa(len(req.IDs)) + "篇文章",
+		"restored_ids":   req.IDs,
+		"restored_count": len(req.IDs),
+	})
+}
+
+// 从回收站彻底删除文章
+func PurgeArticles(ctx *gin.Context) {
+	var req struct {
+		IDs []uint `json:"ids" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := articleService.PurgeArticles(req.IDs); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":      "成功彻底删除 " + strconv.Itoa(len(req.IDs)) + "篇文章",
+		"purged_ids":   req.IDs,
+		"purged_count": len(req.IDs),
+	})
+}
@@ -5,11 +5,14 @@ import (
 	"go_test/model"
 	"go_test/service"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 var authService = service.NewAuthService()
+var tokenService = service.NewTokenService()
 
 // Register 用户注册
 func Register(c *gin.Context) {
@@ -19,11 +22,11 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	response, err := authService.Register(user)
+	response, err := authService.Register(user, clientInfo(c))
 	if err != nil {
-		if err.Error() == "角色参数无效，只能是admin或user" {
+		if err.Error() == "角色参数无效，只能是admin、author或user" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else if err.Error() == "密码加密失败" || err.Error() == "生成令牌失败" {
+		} else if err.Error() == "密码加密失败" || err.Error() == "生成令牌失败" || strings.HasPrefix(err.Error(), "生成登录验证失败") {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -42,11 +45,36 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	response, err := authService.Login(req)
+	response, err := authService.Login(req, clientInfo(c))
 	if err != nil {
-		if err.Error() == "用户不存在" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else if err.Error() == "密码错误" {
+		if err.Error() == "用户名或密码错误" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else if err.Error() == "用户账户已被禁用" {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if strings.HasPrefix(err.Error(), "账户已被临时锁定") || strings.HasPrefix(err.Error(), "登录失败次数过多") ||
+			strings.HasPrefix(err.Error(), "登录尝试过于频繁") {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RefreshToken 使用刷新令牌换取新的访问令牌
+func RefreshToken(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	response, err := tokenService.Refresh(req.RefreshToken, clientInfo(c))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "刷新令牌") || err.Error() == "用户不存在" || err.Error() == "用户账户已被禁用" ||
+			err.Error() == "管理员账户必须启用两步验证，请重新登录" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -56,3 +84,40 @@ func Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// Logout 注销当前设备的登录状态
+func Logout(c *gin.Context) {
+	uid, _, ok := getCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	expiresAt, _ := c.Get("tokenExpiresAt")
+	tokenExpiresAt, _ := expiresAt.(time.Time)
+	if err := tokenService.Logout(uid, c.GetString("sessionID"), c.GetString("tokenID"), tokenExpiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已退出登录"})
+}
+
+// LogoutAll 注销所有设备的登录状态
+func LogoutAll(c *gin.Context) {
+	uid, _, ok := getCurrentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := tokenService.RevokeAllSessions(uid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已退出所有设备"})
+}
+
+// clientInfo 获取客户端IP和User-Agent，用于记录登录会话
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
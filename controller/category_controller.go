@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var categoryService = service.NewCategoryService()
+
+// GetCategories 获取所有分类及文章数量
+func GetCategories(ctx *gin.Context) {
+	categories, err := categoryService.GetCategoriesWithCount()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  categories,
+		"total": len(categories),
+	})
+}
+
+// CreateCategory 创建分类
+func CreateCategory(ctx *gin.Context) {
+	var req dto.CategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	category, err := categoryService.CreateCategory(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory 更新分类
+func UpdateCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "分类ID格式错误"})
+		return
+	}
+
+	var req dto.CategoryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := categoryService.UpdateCategory(uint(id), req); err != nil {
+		if err.Error() == "分类不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "分类名称已存在" || err.Error() == "分类名称不能为空" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "更新分类成功"})
+}
+
+// DeleteCategory 删除分类
+func DeleteCategory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "分类ID格式错误"})
+		return
+	}
+
+	if err := categoryService.DeleteCategory(uint(id)); err != nil {
+		if err.Error() == "分类不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "删除分类成功"})
+}
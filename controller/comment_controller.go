@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/global"
+	"go_test/service"
+	"go_test/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var commentService = service.NewCommentService()
+
+// 分页获取文章评论树
+func GetArticleComments(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role := getOptionalUser(ctx)
+
+	paginate := utils.PaginateFromContext(ctx)
+	response, err := commentService.GetArticleComments(uint(id), paginate, uid, role == global.RoleAdmin)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// 发表评论或回复
+func CreateComment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "文章ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	comment, err := commentService.CreateComment(uint(id), req, uid, role == global.RoleAdmin)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// 编辑自己的评论
+func UpdateComment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "评论ID格式错误"})
+		return
+	}
+
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.CommentUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	comment, err := commentService.UpdateComment(uint(id), req, uid)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comment)
+}
+
+// 删除评论（作者删除自己的评论，管理员可删除任意评论）
+func DeleteComment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "评论ID格式错误"})
+		return
+	}
+
+	uid, role, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := commentService.DeleteComment(uint(id), uid, role == global.RoleAdmin); err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "删除评论成功"})
+}
+
+// 管理员隐藏评论
+func HideComment(ctx *gin.Context) {
+	moderateComment(ctx, global.CommentStatusHidden)
+}
+
+// 管理员审核通过评论
+func ApproveComment(ctx *gin.Context) {
+	moderateComment(ctx, global.CommentStatusApproved)
+}
+
+// 按状态分页查询评论（如审核队列 ?status=pending）
+func GetCommentsByStatus(ctx *gin.Context) {
+	status := ctx.DefaultQuery("status", global.CommentStatusPending)
+	paginate := utils.PaginateFromContext(ctx)
+
+	response, err := commentService.GetCommentsByStatus(status, paginate)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func moderateComment(ctx *gin.Context, status string) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "评论ID格式错误"})
+		return
+	}
+
+	comment, err := commentService.ModerateComment(uint(id), status)
+	if err != nil {
+		respondCommentError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comment)
+}
+
+func respondCommentError(ctx *gin.Context, err error) {
+	if err.Error() == "未找到该文章" || err.Error() == "未找到该评论" {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	} else if strings.HasPrefix(err.Error(), "权限不足") {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	} else if err.Error() == "评论状态已变更，请刷新后重试" {
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	} else if err.Error() == "评论内容不能为空" || err.Error() == "回复的评论不存在" || err.Error() == "评论状态参数无效" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getCurrentUser 从JWT中间件写入的上下文中获取当前用户ID和角色，失败时直接写入错误响应
+func getCurrentUser(ctx *gin.Context) (uint, string, bool) {
+	userID, exists := ctx.Get("userID")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未找到用户信息"})
+		return 0, "", false
+	}
+
+	uid, ok := userID.(uint)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "用户ID格式错误"})
+		return 0, "", false
+	}
+
+	userRole, exists := ctx.Get("userRole")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "未找到用户角色信息"})
+		return 0, "", false
+	}
+
+	role, ok := userRole.(string)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "用户角色格式错误"})
+		return 0, "", false
+	}
+
+	return uid, role, true
+}
+
+// getOptionalUser 获取可选认证的当前用户，匿名访问时返回0和空角色
+func getOptionalUser(ctx *gin.Context) (uint, string) {
+	uid, _ := ctx.Get("userID")
+	role, _ := ctx.Get("userRole")
+	userID, _ := uid.(uint)
+	userRole, _ := role.(string)
+	return userID, userRole
+}
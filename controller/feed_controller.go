@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"go_test/service"
+	"go_test/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var feedService = service.NewFeedService()
+
+// RSS 2.0订阅源 - 支持 /tag/:name 和 /author/:id 前缀
+func GetRSSFeed(ctx *gin.Context) {
+	serveFeed(ctx, service.FeedFormatRSS)
+}
+
+// Atom订阅源 - 支持 /tag/:name 和 /author/:id 前缀
+func GetAtomFeed(ctx *gin.Context) {
+	serveFeed(ctx, service.FeedFormatAtom)
+}
+
+// JSON Feed订阅源 - 支持 /tag/:name 和 /author/:id 前缀
+func GetJSONFeed(ctx *gin.Context) {
+	serveFeed(ctx, service.FeedFormatJSON)
+}
+
+// serveFeed 输出订阅源，支持ETag/Last-Modified条件请求
+func serveFeed(ctx *gin.Context, format string) {
+	var scope service.FeedScope
+	if tag := ctx.Param("name"); tag != "" {
+		scope.Tag = tag
+	}
+	if idStr := ctx.Param("id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil || id == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "无效的作者ID"})
+			return
+		}
+		scope.AuthorID = uint(id)
+	}
+
+	feed, err := feedService.GetFeed(format, scope)
+	if err != nil {
+		if err.Error() == "标签不存在" || err.Error() == "用户不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("ETag", feed.ETag)
+	ctx.Header("Last-Modified", feed.LastModified.UTC().Format(http.TimeFormat))
+	ctx.Header("Cache-Control", "public, max-age=300")
+	if utils.IsNotModified(ctx.Request, feed.ETag, feed.LastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, feed.ContentType, []byte(feed.Body))
+}
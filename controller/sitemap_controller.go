@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"go_test/service"
+	"go_test/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var sitemapService = service.NewSitemapService()
+
+// 站点地图入口，URL较多时返回站点地图索引
+func GetSitemap(ctx *gin.Context) {
+	serveSitemap(ctx, "")
+}
+
+// 站点地图子文件（pages-N.xml、articles-N.xml）
+func GetSitemapFile(ctx *gin.Context) {
+	serveSitemap(ctx, ctx.Param("name"))
+}
+
+func serveSitemap(ctx *gin.Context, name string) {
+	sitemap, err := sitemapService.GetSitemap(name)
+	if err != nil {
+		if err.Error() == "未找到该站点地图" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "站点地图正在生成，请稍后重试" {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.Header("ETag", sitemap.ETag)
+	ctx.Header("Last-Modified", sitemap.LastModified.UTC().Format(http.TimeFormat))
+	if utils.IsNotModified(ctx.Request, sitemap.ETag, sitemap.LastModified) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", []byte(sitemap.Body))
+}
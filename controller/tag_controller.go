@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var tagService = service.NewTagService()
+
+// GetTags 获取所有标签及文章数量
+func GetTags(ctx *gin.Context) {
+	tags, err := tagService.GetTagsWithCount()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  tags,
+		"total": len(tags),
+	})
+}
+
+// CreateTag 创建标签
+func CreateTag(ctx *gin.Context) {
+	var req dto.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	tag, err := tagService.CreateTag(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag 重命名标签
+func UpdateTag(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "标签ID格式错误"})
+		return
+	}
+
+	var req dto.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := tagService.UpdateTag(uint(id), req); err != nil {
+		if err.Error() == "标签不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else if err.Error() == "标签名称已存在" || err.Error() == "标签名称不能为空" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "更新标签成功"})
+}
+
+// DeleteTag 删除标签
+func DeleteTag(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "标签ID格式错误"})
+		return
+	}
+
+	if err := tagService.DeleteTag(uint(id)); err != nil {
+		if err.Error() == "标签不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "删除标签成功"})
+}
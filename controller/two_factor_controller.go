@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"go_test/dto"
+	"go_test/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var twoFactorService = service.NewTwoFactorService()
+
+// SetupTwoFactor 生成两步验证密钥和otpauth地址，需再调用确认接口才会生效
+func SetupTwoFactor(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	setup, err := twoFactorService.Setup(uid)
+	if err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": setup})
+}
+
+// ConfirmTwoFactor 输入验证器App生成的验证码确认启用两步验证，返回恢复码
+func ConfirmTwoFactor(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	codes, err := twoFactorService.Confirm(uid, req.Code)
+	if err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":        "两步验证已启用，请妥善保存恢复码",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor 关闭两步验证
+func DisableTwoFactor(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.TwoFactorDisableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	if err := twoFactorService.Disable(uid, req.Password, req.Code, clientInfo(ctx).IP); err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "两步验证已关闭"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧恢复码作废
+func RegenerateRecoveryCodes(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "参数错误: " + err.Error()})
+		return
+	}
+
+	codes, err := twoFactorService.RegenerateRecoveryCodes(uid, req.Code, clientInfo(ctx).IP)
+	if err != nil {
+		handleTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// SetupTwoFactorForLogin 登录过程中为必须启用两步验证的账户生成密钥
+func SetupTwoFactorForLogin(c *gin.Context) {
+	var req dto.TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	setup, err := twoFactorService.SetupWithChallenge(req.ChallengeToken)
+	if err != nil {
+		handleTwoFactorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": setup})
+}
+
+// VerifyTwoFactorLogin 登录第二步，校验验证码或恢复码后返回令牌
+func VerifyTwoFactorLogin(c *gin.Context) {
+	var req dto.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "参数错误"})
+		return
+	}
+
+	response, err := twoFactorService.VerifyChallenge(req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		if err.Error() == "验证码错误" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		} else {
+			handleTwoFactorError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleTwoFactorError 两步验证错误到HTTP状态码的映射
+func handleTwoFactorError(ctx *gin.Context, err error) {
+	switch msg := err.Error(); {
+	case msg == "用户不存在":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case msg == "登录验证已过期，请重新登录" || msg == "验证失败次数过多，请重新登录":
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": msg})
+	case msg == "用户账户已被禁用" || msg == "管理员账户必须启用两步验证":
+		ctx.JSON(http.StatusForbidden, gin.H{"error": msg})
+	case strings.HasPrefix(msg, "账户已被临时锁定") || strings.HasPrefix(msg, "登录失败次数过多") ||
+		strings.HasPrefix(msg, "登录尝试过于频繁"):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": msg})
+	case msg == "已启用两步验证" || msg == "未启用两步验证" || msg == "请先生成两步验证密钥" ||
+		msg == "无需绑定两步验证" || msg == "验证码错误" || msg == "密码错误":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
@@ -11,6 +11,8 @@ import (
 )
 
 var userService = service.NewUserService()
+var sessionService = service.NewSessionService()
+var loginGuardService = service.NewLoginGuardService()
 
 // GetMyProfile 获取自己的用户资料
 func GetMyProfile(ctx *gin.Context) {
@@ -189,6 +191,8 @@ func UpdateUserProfile(ctx *gin.Context) {
 	if err != nil {
 		if err.Error() == "邮箱已被其他用户使用" || err.Error() == "没有需要更新的字段" {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if err.Error() == "用户不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		} else {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
@@ -212,3 +216,67 @@ func GetAllUsers(ctx *gin.Context) {
 		"total":   len(users),
 	})
 }
+
+// GetMySessions 获取自己当前有效的登录会话
+func GetMySessions(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	sessions, err := sessionService.ListSessions(uid, ctx.GetString("sessionID"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "获取登录会话失败"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"data":  sessions,
+		"total": len(sessions),
+	})
+}
+
+// DeleteMySession 结束自己的某个登录会话（退出该设备）
+func DeleteMySession(ctx *gin.Context) {
+	uid, _, ok := getCurrentUser(ctx)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "会话ID格式错误"})
+		return
+	}
+
+	if err := sessionService.EndSession(uid, uint(id)); err != nil {
+		if err.Error() == "未找到该会话" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "已结束该会话"})
+}
+
+// UnlockUserLogin 解除用户的登录锁定（管理员功能），可通过 ?ip= 同时解除某个IP的封禁
+func UnlockUserLogin(ctx *gin.Context) {
+	targetUserID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "用户ID格式错误"})
+		return
+	}
+
+	if err := loginGuardService.UnlockUser(uint(targetUserID), ctx.Query("ip")); err != nil {
+		if err.Error() == "用户不存在" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "已解除登录锁定"})
+}
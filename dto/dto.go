@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // Auth相关
 
 type LoginRequest struct {
@@ -8,10 +10,85 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	Token    string `json:"token"`
-	Message  string `json:"message"`
+	Username     string `json:"username"`
+	Role         string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌剩余有效秒数
+	Message      string `json:"message"`
+
+	// 启用两步验证时，登录只返回挑战令牌，需调用 /api/auth/2fa/verify 完成登录
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // 账户必须先绑定两步验证
+	ChallengeToken         string   `json:"challenge_token,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"` // 登录时完成绑定才会返回，只显示一次
+}
+
+// 邮箱验证和找回密码相关
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"` // 新密码，最少6位
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// 两步验证相关
+
+type TwoFactorSetupVO struct {
+	Secret     string `json:"secret"`      // Base32密钥，无法扫码时手动输入
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth://地址，可生成二维码供验证器App扫描
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // 验证码或恢复码
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // 验证码或恢复码
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// ClientInfo 发起登录或刷新的客户端信息，用于记录会话
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type SessionVO struct {
+	ID        uint   `json:"id"`
+	Device    string `json:"device"`
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+	Created   string `json:"created_at"`
+	LastSeen  string `json:"last_seen_at"`
+	Expires   string `json:"expires_at"`
+	Current   bool   `json:"current"` // 是否为当前请求所在的会话
 }
 
 // 用户资料相关
@@ -44,6 +121,9 @@ type UserProfileVO struct {
 	Status   string `json:"status"`
 	Created  string `json:"created_at"`
 	Updated  string `json:"updated_at"`
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"` // 是否已启用两步验证
+	EmailVerified    bool `json:"email_verified"`     // 邮箱是否已验证
 }
 
 // AdminUpdateUserRequest 管理员更新用户请求DTO（包含状态和角色）
@@ -53,7 +133,7 @@ type AdminUpdateUserRequest struct {
 	Nickname string `json:"nickname" binding:"omitempty,max=50"`
 	Bio      string `json:"bio" binding:"omitempty,max=500"`
 	Phone    string `json:"phone" binding:"omitempty,max=20"`
-	Role     string `json:"role" binding:"omitempty,oneof=admin user"`        // 管理员可以修改角色
+	Role     string `json:"role" binding:"omitempty,oneof=admin author user"` // 管理员可以修改角色
 	Status   string `json:"status" binding:"omitempty,oneof=active disabled"` // 管理员可以修改状态
 }
 
@@ -76,15 +156,195 @@ type ExchangeRateVO struct {
 // 文章相关
 
 type ArticleRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	Preview string `json:"preview" binding:"required"`
+	Title      string   `json:"title" binding:"required"`
+	Content    string   `json:"content" binding:"required"`
+	Preview    string   `json:"preview" binding:"required"`
+	Slug       string   `json:"slug" binding:"omitempty,max=80"`      // 可选，为空时根据标题自动生成
+	CategoryID *uint    `json:"category_id"`                          // 分类ID，可选
+	Tags       []string `json:"tags" binding:"omitempty,dive,max=50"` // 标签名称，不存在时自动创建
+}
+
+// ArticleUpdateRequest 更新文章请求DTO（字段为nil表示不修改，支持部分更新）
+type ArticleUpdateRequest struct {
+	Title      *string   `json:"title" binding:"omitempty,min=1"`
+	Content    *string   `json:"content" binding:"omitempty,min=1"`
+	Preview    *string   `json:"preview" binding:"omitempty,min=1"`
+	Slug       *string   `json:"slug" binding:"omitempty,max=80"`      // 修改后旧slug仍可访问（301重定向）
+	CategoryID *uint     `json:"category_id"`                          // 传0表示清除分类
+	Tags       *[]string `json:"tags" binding:"omitempty,dive,max=50"` // 传空数组表示清除所有标签
+}
+
+// ArticleFilter 文章列表筛选条件
+type ArticleFilter struct {
+	Keyword   string `form:"keyword"`
+	Tag       string `form:"tag"`                                               // 标签名称
+	Category  string `form:"category"`                                          // 分类名称
+	Highlight string `form:"highlight" binding:"omitempty,oneof=mark markdown"` // 搜索结果的高亮标记，默认取配置
+}
+
+// ArticleTransitionRequest 文章状态流转请求DTO
+type ArticleTransitionRequest struct {
+	Status    string     `json:"status" binding:"required,oneof=draft in_review scheduled published archived"` // 目标状态
+	Comment   string     `json:"comment" binding:"omitempty,max=500"`                                          // 备注，如审核意见
+	PublishAt *time.Time `json:"publish_at"`                                                                   // 定时发布时间，流转到scheduled时必填
 }
 
 type ArticleVO struct {
-	ID      uint   `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Preview string `json:"preview"`
-	Created string `json:"created_at"`
+	ID        uint   `json:"id"`
+	Slug      string `json:"slug"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Preview   string `json:"preview"`
+	Version   uint   `json:"version"`
+	Status    string `json:"status"`
+	Published string `json:"published_at,omitempty"`
+	PublishAt string `json:"publish_at,omitempty"` // 定时发布时间
+	Created   string `json:"created_at"`
+	Deleted   string `json:"deleted_at,omitempty"` // 仅回收站列表返回
+
+	// 渲染结果
+	ContentHTML string    `json:"content_html"` // 经过白名单过滤的HTML
+	TOC         []TOCItem `json:"toc"`          // 根据标题生成的目录
+
+	// 作者信息
+	AuthorID       uint   `json:"author_id"`
+	AuthorNickname string `json:"author_nickname"`
+	AuthorAvatar   string `json:"author_avatar"`
+
+	// 分类与标签
+	CategoryID   uint     `json:"category_id,omitempty"`
+	CategoryName string   `json:"category_name,omitempty"`
+	Tags         []string `json:"tags"`
+
+	CommentCount int64 `json:"comment_count"` // 已通过审核的评论数
+
+	// 搜索结果
+	Snippet       string   `json:"snippet,omitempty"`        // 正文中命中位置附近的高亮摘要
+	MatchedFields []string `json:"matched_fields,omitempty"` // 命中的字段：title、content
+}
+
+// TOCItem 文章目录项
+type TOCItem struct {
+	Level    int       `json:"level"`
+	ID       string    `json:"id"` // 标题锚点
+	Text     string    `json:"text"`
+	Children []TOCItem `json:"children"`
+}
+
+// ArticleStatusLogVO 文章状态流转记录响应DTO
+type ArticleStatusLogVO struct {
+	ID         uint   `json:"id"`
+	ArticleID  uint   `json:"article_id"`
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	OperatorID uint   `json:"operator_id"`
+	Comment    string `json:"comment"`
+	Created    string `json:"created_at"`
+}
+
+// ArticleRevisionVO 文章修订记录响应DTO
+type ArticleRevisionVO struct {
+	ID        uint   `json:"id"`
+	ArticleID uint   `json:"article_id"`
+	Version   uint   `json:"version"`
+	Title     string `json:"title"`
+	Content   string `json:"content,omitempty"` // 列表中不返回正文
+	Preview   string `json:"preview"`
+	EditorID  uint   `json:"editor_id"`
+	Comment   string `json:"comment"`
+	Created   string `json:"created_at"`
+}
+
+// 标签与分类相关
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type TagVO struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	ArticleCount int64  `json:"article_count"` // 已发布文章数量
+	Created      string `json:"created_at"`
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+type CategoryVO struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	ArticleCount int64  `json:"article_count"` // 已发布文章数量
+	Created      string `json:"created_at"`
+}
+
+// 评论相关
+
+// CommentRequest 发表评论请求DTO
+type CommentRequest struct {
+	Body     string `json:"body" binding:"required,max=2000"`
+	ParentID *uint  `json:"parent_id"` // 回复的评论ID，为空表示顶层评论
+}
+
+// CommentUpdateRequest 编辑评论请求DTO
+type CommentUpdateRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// CommentVO 评论响应DTO，Replies为回复树
+// 已删除或不可见的评论在回复树中只作为占位，仅返回id、parent_id、deleted和replies
+type CommentVO struct {
+	ID             uint        `json:"id"`
+	ArticleID      uint        `json:"article_id,omitempty"`
+	ParentID       *uint       `json:"parent_id,omitempty"`
+	AuthorID       uint        `json:"author_id,omitempty"`
+	AuthorNickname string      `json:"author_nickname"`
+	AuthorAvatar   string      `json:"author_avatar"`
+	Body           string      `json:"body"`
+	Status         string      `json:"status,omitempty"`
+	Deleted        bool        `json:"deleted,omitempty"`
+	Created        string      `json:"created_at,omitempty"`
+	Updated        string      `json:"updated_at,omitempty"`
+	Replies        []CommentVO `json:"replies"`
+}
+
+// LikeDriftVO 点赞数据在Redis与MySQL之间的差异
+type LikeDriftVO struct {
+	ArticleID      uint   `json:"article_id"`
+	RedisCount     int    `json:"redis_count"`
+	MySQLCount     int    `json:"mysql_count"`
+	MissingInRedis []uint `json:"missing_in_redis"` // MySQL中有、Redis中没有的点赞用户
+	MissingInMySQL []uint `json:"missing_in_mysql"` // Redis中有、MySQL中没有的点赞用户
+}
+
+// ArticleStatVO 文章每日浏览统计
+type ArticleStatVO struct {
+	Date          string `json:"date"`
+	Views         int64  `json:"views"`
+	UniqueViewers int64  `json:"unique_viewers"`
+}
+
+// 文章导入导出相关
+
+// ArticleImportItem 单个文件的导入结果
+type ArticleImportItem struct {
+	File      string `json:"file"`
+	Title     string `json:"title,omitempty"`
+	Slug      string `json:"slug,omitempty"`
+	Action    string `json:"action"`               // create：新建（试运行时为将要新建）；conflict：slug冲突已跳过；invalid：文件格式错误已跳过
+	Reason    string `json:"reason,omitempty"`     // 跳过原因
+	ArticleID uint   `json:"article_id,omitempty"` // 新建的文章ID，试运行时为空
+}
+
+// ArticleImportReport 导入报告
+type ArticleImportReport struct {
+	DryRun    bool                `json:"dry_run"`
+	Total     int                 `json:"total"`
+	Created   int                 `json:"created"`
+	Conflicts int                 `json:"conflicts"`
+	Invalid   int                 `json:"invalid"`
+	Items     []ArticleImportItem `json:"items"`
 }
@@ -17,7 +17,43 @@ const (
 	CacheKeyExchangeRate = CachePrefix + "exchange_rate"
 
 	// 点赞相关缓存键
-	CacheKeyArticleLikes = CachePrefix + "article:likes"
+	CacheKeyArticleLikes       = CachePrefix + "article:likes"        // 点赞用户集合前缀，完整键为 article:likes:<文章ID>
+	CacheKeyArticleLikesDirty  = CachePrefix + "article:likes_dirty"  // 待回写MySQL的文章ID集合
+	CacheKeyArticleLikesLoaded = CachePrefix + "article:likes_loaded" // 点赞数据已从MySQL加载的标记，缺失说明Redis被清空
+
+	// 浏览统计相关缓存键
+	CacheKeyArticleViews = CachePrefix + "article:views" // 每日浏览次数哈希，完整键为 article:views:<YYYYMMDD>，field为文章ID
+	CacheKeyArticleUV    = CachePrefix + "article:uv"    // 每日独立访客HyperLogLog，完整键为 article:uv:<YYYYMMDD>:<文章ID>
+
+	// 排行榜相关缓存键
+	CacheKeyTrendingBucket  = CachePrefix + "article:trending"          // 每小时热度分桶前缀，完整键为 article:trending:<YYYYMMDDHH>
+	CacheKeyTrendingWindow  = CachePrefix + "article:trending_window"   // 按时间窗口合并后的热度榜，完整键为 article:trending_window:<窗口>
+	CacheKeyLeaderboardLike = CachePrefix + "article:leaderboard:likes" // 累计点赞榜，score为点赞数
+
+	// 订阅源缓存键
+	CacheKeyFeed = CachePrefix + "feed" // 订阅源前缀，完整键为 feed:<格式>:<范围>
+
+	// 站点地图缓存键
+	CacheKeySitemapArticles = CachePrefix + "sitemap:articles" // 已发布文章ID有序集合，score为文章ID，用于稳定分页
+	CacheKeySitemapEntries  = CachePrefix + "sitemap:entries"  // 文章ID -> slug和更新时间
+	CacheKeySitemapLoaded   = CachePrefix + "sitemap:loaded"   // 站点地图数据已从MySQL加载的标记
+	CacheKeySitemapPending  = CachePrefix + "sitemap:pending"  // 全量加载期间变更的文章ID集合，加载完成后再增量更新
+	CacheKeySitemapRender   = CachePrefix + "sitemap:render"   // 渲染结果前缀，完整键为 sitemap:render:<文件名>
+
+	// 认证相关缓存键
+	CacheKeyTokenDenylist   = CachePrefix + "auth:denylist"         // 已注销的访问令牌，完整键为 auth:denylist:<jti>，过期时间与令牌一致
+	CacheKeySessionDenylist = CachePrefix + "auth:session_denylist" // 已结束的登录会话，完整键为 auth:session_denylist:<sid>，保留到会话内最后一个访问令牌过期
+	CacheKeySessionSeen     = CachePrefix + "auth:session_seen"     // 会话最近活跃时间的写入节流标记，完整键为 auth:session_seen:<sid>
+	CacheKeyLoginFail       = CachePrefix + "auth:login_fail"       // 登录失败计数，完整键为 auth:login_fail:<user|ip>:<值>
+	CacheKeyLoginLock       = CachePrefix + "auth:login_lock"       // 登录临时锁定，完整键为 auth:login_lock:<user|ip>:<值>
+	CacheKeyLoginBackoff    = CachePrefix + "auth:login_backoff"    // 失败后的等待期，完整键为 auth:login_backoff:<user|ip>:<值>
+	CacheKeyTwoFactor       = CachePrefix + "auth:2fa_challenge"    // 登录第二步的挑战，完整键为 auth:2fa_challenge:<令牌摘要>
+	CacheKeyRateLimit       = CachePrefix + "ratelimit"             // 接口限流计数，完整键为 ratelimit:<场景>:<IP/邮箱/用户ID>
+
+	// 分布式锁键
+	CacheKeyLockArticleScheduler = CachePrefix + "lock:article_scheduler"
+	CacheKeyLockLikeRebuild      = CachePrefix + "lock:like_rebuild"
+	CacheKeyLockSitemapRebuild   = CachePrefix + "lock:sitemap_rebuild"
 )
 
 // 缓存过期时间（秒）
@@ -42,8 +78,25 @@ const (
 
 // 用户角色常量
 const (
-	RoleAdmin = "admin"
-	RoleUser  = "user"
+	RoleAdmin  = "admin"
+	RoleAuthor = "author" // 作者，只能编辑自己的文章
+	RoleUser   = "user"
+)
+
+// 文章状态常量
+const (
+	ArticleStatusDraft     = "draft"     // 草稿
+	ArticleStatusInReview  = "in_review" // 审核中
+	ArticleStatusScheduled = "scheduled" // 定时发布，到达publish_at后自动发布
+	ArticleStatusPublished = "published" // 已发布
+	ArticleStatusArchived  = "archived"  // 已归档
+)
+
+// 评论状态常量
+const (
+	CommentStatusPending  = "pending"  // 待审核，仅作者本人和管理员可见
+	CommentStatusApproved = "approved" // 已通过
+	CommentStatusHidden   = "hidden"   // 已被管理员隐藏
 )
 
 // 用户状态常量
@@ -51,3 +104,9 @@ const (
 	UserStatusActive   = "active"
 	UserStatusDisabled = "disabled"
 )
+
+// 邮件一次性令牌用途常量
+const (
+	TokenPurposeVerifyEmail   = "verify_email"   // 邮箱验证
+	TokenPurposeResetPassword = "reset_password" // 重置密码
+)
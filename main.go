@@ -1,10 +1,19 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go_test/config"
 	"go_test/model"
 	"go_test/router"
+	"go_test/service"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,10 +24,74 @@ func main() {
 	// 自动迁移数据库表
 	model.AutoMigrate()
 
+	// 初始化全文搜索引擎
+	if err := service.InitSearchEngine(); err != nil {
+		log.Fatalf("初始化搜索引擎失败: %v", err)
+	}
+
+	// 命令行子命令（import/export），执行完直接退出
+	if len(os.Args) > 1 && isCommand(os.Args[1]) {
+		runCommand(os.Args[1:])
+		return
+	}
+
+	// 初始化邮件发送
+	if err := service.InitMailer(); err != nil {
+		log.Fatalf("初始化邮件发送失败: %v", err)
+	}
+
+	// 收到退出信号时取消ctx，通知后台任务退出
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	articleService := service.NewArticleService()
+
+	// 为历史文章生成slug
+	articleService.BackfillArticleSlugs()
+
+	// 启动回收站自动清理任务
+	articleService.StartTrashPurgeJob(ctx)
+
+	// 启动定时发布调度器
+	scheduler := service.NewArticleScheduler()
+	scheduler.Start(ctx)
+
+	// 启动点赞回写任务（Redis数据缺失时先从MySQL重建）
+	likeWorker := service.NewLikeSyncWorker()
+	likeWorker.Start(ctx)
+
+	// 启动浏览统计任务
+	statsWorker := service.NewArticleStatsWorker()
+	statsWorker.Start(ctx)
+
 	ginServer := gin.Default()
+	// 只采信可信代理转发的客户端IP，否则任何人都能伪造 X-Forwarded-For 绕过按IP的限流
+	if err := ginServer.SetTrustedProxies(config.GetAppConfig().TrustedProxies); err != nil {
+		log.Fatalf("可信代理配置无效: %v", err)
+	}
 
 	router.RegisterRoutes(ginServer)
 
 	addr := fmt.Sprintf(":%d", config.GetAppConfig().Port)
-	ginServer.Run(addr)
+	server := &http.Server{Addr: addr, Handler: ginServer}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("服务启动失败: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("正在关闭服务...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("服务关闭失败: %v", err)
+	}
+
+	// 等待调度器完成当前批次，点赞回写和浏览统计任务完成最后一次持久化
+	scheduler.Wait()
+	likeWorker.Wait()
+	statsWorker.Wait()
+	log.Println("服务已退出")
 }
@@ -5,12 +5,18 @@ import (
 	"go_test/global"
 	"go_test/model"
 	"go_test/utils"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// sessionTouchInterval 会话最近活跃时间的写入间隔
+const sessionTouchInterval = 5 * time.Minute
+
 // UserInfo 用户信息结构
 type UserInfo struct {
 	Username string
@@ -39,6 +45,16 @@ func (v *AdminRoleValidator) Validate(ctx *AuthContext) error {
 	return nil
 }
 
+// AuthorRoleValidator 作者角色验证器（作者或管理员）
+type AuthorRoleValidator struct{}
+
+func (v *AuthorRoleValidator) Validate(ctx *AuthContext) error {
+	if ctx.UserClaims.Role != global.RoleAdmin && ctx.UserClaims.Role != global.RoleAuthor {
+		return errors.New("权限不足，仅作者或管理员可访问")
+	}
+	return nil
+}
+
 // DatabaseStatusValidator 数据库状态验证器
 type DatabaseStatusValidator struct{}
 
@@ -120,14 +136,27 @@ func authMiddleware(validators ...Validator) gin.HandlerFunc {
 			return
 		}
 
-		// 2. 验证JWT中必须包含用户ID
-		if userClaims.UserID == 0 {
+		// 2. 验证JWT中必须包含用户ID、令牌ID和会话ID
+		if userClaims.UserID == 0 || userClaims.ID == "" || userClaims.SessionID == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token格式无效，请重新登录"})
 			c.Abort()
 			return
 		}
 
-		// 3. 初始化认证上下文
+		// 3. 检查令牌或所属会话是否已注销（Redis不可用时改查数据库中的会话，都不可用时拒绝访问）
+		revoked, err := isTokenRevoked(userClaims)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "认证服务暂不可用，请稍后重试"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token已失效，请重新登录"})
+			c.Abort()
+			return
+		}
+
+		// 4. 初始化认证上下文
 		authCtx := &AuthContext{
 			UserClaims: userClaims,
 			UserInfo: &UserInfo{
@@ -137,7 +166,7 @@ func authMiddleware(validators ...Validator) gin.HandlerFunc {
 			},
 		}
 
-		// 4. 依次执行所有验证器
+		// 5. 依次执行所有验证器
 		for _, validator := range validators {
 			if err := validator.Validate(authCtx); err != nil {
 				// 根据错误类型返回不同的状态码
@@ -151,10 +180,14 @@ func authMiddleware(validators ...Validator) gin.HandlerFunc {
 			}
 		}
 
-		// 5. 将最终的用户信息存入上下文
+		// 6. 将最终的用户信息和令牌信息存入上下文
 		c.Set("username", authCtx.UserInfo.Username)
 		c.Set("userRole", authCtx.UserInfo.Role)
 		c.Set("userID", authCtx.UserInfo.UserID)
+		c.Set("tokenID", userClaims.ID)
+		c.Set("sessionID", userClaims.SessionID)
+		c.Set("tokenExpiresAt", userClaims.ExpiresAt)
+		touchSession(userClaims.SessionID, c.ClientIP())
 		c.Next()
 	}
 }
@@ -164,11 +197,65 @@ func AuthMiddleware() gin.HandlerFunc {
 	return authMiddleware() // 只有基础JWT认证，无额外验证器
 }
 
+// OptionalAuthMiddleware 可选认证中间件，携带有效Token时写入用户信息，未携带、无效、已注销或无法校验时按匿名访问放行
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userClaims, err := parseTokenFromRequest(c)
+		if err == nil && userClaims.UserID != 0 && userClaims.ID != "" && userClaims.SessionID != "" {
+			// 无法确认是否已注销时同样按匿名访问
+			if revoked, err := isTokenRevoked(userClaims); err == nil && !revoked {
+				c.Set("username", userClaims.Username)
+				c.Set("userRole", userClaims.Role)
+				c.Set("userID", userClaims.UserID)
+			}
+		}
+		c.Next()
+	}
+}
+
+// isTokenRevoked 令牌或其所属会话是否已注销
+// Redis不可用时改查数据库中的会话记录：注销（包括退出登录）都会标记会话，只是无法识别单独拉黑的令牌
+func isTokenRevoked(claims *utils.UserClaims) (bool, error) {
+	revoked, err := utils.IsTokenRevoked(claims.ID, claims.SessionID)
+	if err == nil {
+		return revoked, nil
+	}
+	log.Printf("查询Token黑名单失败，改查数据库会话: %v", err)
+
+	var session model.UserSession
+	err = global.DB.Select("id", "revoked_at").Where("session_id = ? AND user_id = ?", claims.SessionID, claims.UserID).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		log.Printf("查询会话状态失败: %v", err)
+		return false, err
+	}
+	return session.RevokedAt != nil, nil
+}
+
+// touchSession 更新会话的最近活跃时间和IP，通过Redis节流，每个会话每隔sessionTouchInterval最多写一次数据库
+func touchSession(sessionID, clientIP string) {
+	ok, err := utils.ShouldTouchSession(sessionID, sessionTouchInterval)
+	if err != nil || !ok {
+		return
+	}
+	if err := global.DB.Model(&model.UserSession{}).Where("session_id = ?", sessionID).
+		Updates(map[string]interface{}{"last_seen_at": time.Now(), "ip": clientIP}).Error; err != nil {
+		log.Printf("更新会话活跃时间失败: %v", err)
+	}
+}
+
 // AdminOnlyMiddleware 管理员中间件（JWT + 角色验证）
 func AdminOnlyMiddleware() gin.HandlerFunc {
 	return authMiddleware(&AdminRoleValidator{})
 }
 
+// AuthorOrAdminMiddleware 作者中间件（JWT + 作者/管理员角色验证）
+func AuthorOrAdminMiddleware() gin.HandlerFunc {
+	return authMiddleware(&AuthorRoleValidator{})
+}
+
 // SensitiveAdminMiddleware 敏感操作中间件（数据库实时验证管理员权限）
 func SensitiveAdminMiddleware() gin.HandlerFunc {
 	return authMiddleware(&DatabaseAdminValidator{})
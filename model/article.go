@@ -1,10 +1,29 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Article struct {
 	gorm.Model
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
-	Preview string `json:"preview" binding:"required"`
+	Title        string     `json:"title" binding:"required"`
+	Content      string     `json:"content" binding:"required"`
+	Preview      string     `json:"preview" binding:"required"`
+	ContentHTML  string     `gorm:"type:longtext" json:"-"`                        // 正文渲染后的HTML缓存
+	ContentTOC   string     `gorm:"type:text" json:"-"`                            // 正文目录缓存（JSON）
+	Slug         string     `gorm:"size:191;uniqueIndex;default:null" json:"slug"` // 当前slug，历史slug保存在ArticleSlug中；尚未生成时为NULL，不占用唯一索引
+	Version      uint       `gorm:"not null;default:1" json:"version"`             // 乐观锁版本号，每次更新自增
+	AuthorID     *uint      `gorm:"index" json:"author_id"`                        // 作者ID，历史文章可能为空
+	Author       *User      `gorm:"foreignKey:AuthorID" json:"-"`
+	Status       string     `gorm:"size:20;not null;default:'published';index" json:"status"` // draft/in_review/published/archived，历史文章默认为已发布
+	PublishedAt  *time.Time `json:"published_at"`                                             // 首次发布时间
+	PublishAt    *time.Time `gorm:"index" json:"publish_at"`                                  // 定时发布时间，仅scheduled状态有效
+	CategoryID   *uint      `gorm:"index" json:"category_id"`                                 // 分类ID，可为空
+	Category     *Category  `gorm:"foreignKey:CategoryID" json:"-"`
+	Tags         []Tag      `gorm:"many2many:article_tags;" json:"-"`
+	CommentCount int64      `gorm:"not null;default:0" json:"comment_count"` // 已通过审核的评论数
+	LikeCount    int64      `gorm:"not null;default:0" json:"like_count"`    // 点赞数，由点赞回写任务同步
+	LegacyLikes  int64      `gorm:"not null;default:0" json:"-"`             // 旧版本的匿名点赞计数，无法对应到用户，计入点赞数
 }
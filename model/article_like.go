@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// ArticleLike 文章点赞记录，由Redis中的点赞用户集合异步回写
+type ArticleLike struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"not null;uniqueIndex:idx_article_user" json:"article_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_article_user;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
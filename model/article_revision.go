@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// ArticleRevision 文章修订快照，每次创建或更新文章时保存一份
+type ArticleRevision struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"not null;uniqueIndex:idx_article_version" json:"article_id"`
+	Version   uint      `gorm:"not null;uniqueIndex:idx_article_version" json:"version"` // 对应文章的版本号
+	Title     string    `gorm:"not null" json:"title"`
+	Content   string    `gorm:"type:longtext" json:"content"`
+	Preview   string    `json:"preview"`
+	EditorID  uint      `gorm:"not null" json:"editor_id"` // 修改人ID
+	Comment   string    `gorm:"size:255" json:"comment"`   // 修订说明，如"回滚到版本3"
+	CreatedAt time.Time `json:"created_at"`
+}
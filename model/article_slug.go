@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// ArticleSlug 文章slug记录，保留历史slug用于301重定向
+// slug的全局唯一性（包括历史slug）由该表的唯一索引保证，文章表的当前slug也有唯一索引
+type ArticleSlug struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArticleID uint      `gorm:"index;not null" json:"article_id"`
+	Slug      string    `gorm:"size:191;uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ArticleStat 文章每日浏览统计，由Redis中的计数定期持久化
+type ArticleStat struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	ArticleID     uint      `gorm:"not null;uniqueIndex:idx_article_date" json:"article_id"`
+	Date          time.Time `gorm:"type:date;not null;uniqueIndex:idx_article_date;index" json:"date"`
+	Views         int64     `gorm:"not null;default:0" json:"views"`          // 浏览次数
+	UniqueViewers int64     `gorm:"not null;default:0" json:"unique_viewers"` // 独立访客数（HyperLogLog估算）
+	UpdatedAt     time.Time `json:"updated_at"`
+}
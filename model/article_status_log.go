@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ArticleStatusLog 文章状态流转记录
+type ArticleStatusLog struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	ArticleID  uint      `gorm:"index;not null" json:"article_id"`
+	FromStatus string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"`
+	OperatorID uint      `gorm:"not null" json:"operator_id"` // 操作人ID
+	Comment    string    `gorm:"size:500" json:"comment"`     // 备注，如审核意见
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment 文章评论，通过ParentID形成回复树
+type Comment struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	ArticleID uint           `gorm:"not null;index" json:"article_id"`
+	AuthorID  uint           `gorm:"not null;index" json:"author_id"`
+	Author    *User          `gorm:"foreignKey:AuthorID" json:"-"`
+	ParentID  *uint          `gorm:"index" json:"parent_id"` // 父评论ID，顶层评论为空
+	RootID    *uint          `gorm:"index" json:"root_id"`   // 所属顶层评论ID，用于整楼加载回复，顶层评论为空
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	Status    string         `gorm:"size:20;not null;default:'approved';index" json:"status"` // pending/approved/hidden
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -7,9 +7,48 @@ import (
 
 // AutoMigrate 自动迁移数据库表结构
 func AutoMigrate() {
-	err := global.DB.AutoMigrate(&User{}, &ExchangeRate{}, &Article{})
+	if err := migrateArticleSlugIndex(); err != nil {
+		log.Fatalf("迁移文章slug索引失败: %v", err)
+	}
+
+	err := global.DB.AutoMigrate(
+		&User{}, &ExchangeRate{}, &Category{}, &Tag{}, &Article{},
+		&ArticleStatusLog{}, &ArticleRevision{}, &ArticleSlug{}, &Comment{}, &ArticleLike{}, &ArticleStat{},
+		&RefreshToken{}, &UserSession{}, &RecoveryCode{}, &UserToken{},
+	)
 	if err != nil {
 		log.Fatalf("数据库迁移失败: %v", err)
 	}
 	log.Println("数据库迁移成功")
 }
+
+// migrateArticleSlugIndex 将文章slug的普通索引升级为唯一索引
+// 空slug改为NULL（唯一索引允许多个NULL），重复的slug只保留ID最小的文章，其余置空后由 BackfillArticleSlugs 重新生成
+func migrateArticleSlugIndex() error {
+	const indexName = "idx_articles_slug"
+	migrator := global.DB.Migrator()
+	if !migrator.HasTable(&Article{}) || !migrator.HasIndex(&Article{}, indexName) {
+		return nil
+	}
+	indexes, err := migrator.GetIndexes(&Article{})
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if index.Name() != indexName {
+			continue
+		}
+		if unique, ok := index.Unique(); ok && unique {
+			return nil
+		}
+	}
+
+	if err := global.DB.Exec("UPDATE articles SET slug = NULL WHERE slug = ''").Error; err != nil {
+		return err
+	}
+	if err := global.DB.Exec("UPDATE articles a JOIN articles b ON a.slug = b.slug AND a.id > b.id SET a.slug = NULL").Error; err != nil {
+		return err
+	}
+	// 删除旧的普通索引，随后由AutoMigrate按新的定义创建唯一索引
+	return migrator.DropIndex(&Article{}, indexName)
+}
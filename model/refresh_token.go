@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// RefreshToken 刷新令牌，只保存SHA-256摘要
+// 每次刷新都会签发新令牌并标记旧令牌已使用；同一次登录轮换出的令牌属于同一个FamilyID
+type RefreshToken struct {
+	ID              uint       `gorm:"primarykey" json:"id"`
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	FamilyID        string     `gorm:"size:64;not null;index" json:"family_id"`
+	TokenHash       string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	AccessJTI       string     `gorm:"size:64;not null" json:"-"` // 同时签发的访问令牌ID，撤销时加入黑名单
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at"`                 // 已轮换的时间，再次使用视为令牌泄露
+	RevokedAt       *time.Time `gorm:"index" json:"revoked_at"` // 注销时间
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Tag 文章标签，与文章为多对多关系
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"size:50;uniqueIndex;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -15,4 +19,11 @@ type User struct {
 	Nickname string `gorm:"size:50" json:"nickname"`      // 昵称
 	Bio      string `gorm:"type:text" json:"bio"`         // 个人简介
 	Phone    string `gorm:"size:20" json:"phone"`         // 电话号码
+
+	EmailVerifiedAt *time.Time `json:"-"` // 邮箱验证时间，修改邮箱后清空
+
+	// 两步验证，不参与JSON绑定
+	TOTPSecret   string `gorm:"size:255" json:"-"` // 加密后的TOTP密钥，启用前为待确认的密钥
+	TOTPEnabled  bool   `gorm:"not null;default:false" json:"-"`
+	TOTPLastStep int64  `gorm:"not null;default:0" json:"-"` // 最近一次使用的时间步，防止验证码重放
 }
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserSession 登录会话，每次登录创建一条，刷新令牌时更新
+type UserSession struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	SessionID  string     `gorm:"size:64;not null;uniqueIndex" json:"-"` // 与JWT中的sid、刷新令牌的FamilyID一致
+	TokenID    string     `gorm:"size:64" json:"-"`                      // 最近签发的访问令牌ID
+	Device     string     `gorm:"size:100" json:"device"`                // 根据User-Agent识别的设备名称
+	UserAgent  string     `gorm:"size:500" json:"user_agent"`
+	IP         string     `gorm:"size:64" json:"ip"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`              // 随刷新令牌延长
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at"` // 会话结束时间
+	CreatedAt  time.Time  `json:"created_at"`
+}
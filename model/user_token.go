@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// UserToken 邮件中一次性签名令牌的登记记录，令牌本身由签名保证不可伪造，这里只记录是否已使用
+type UserToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Purpose   string     `gorm:"size:32;not null" json:"purpose"`
+	Nonce     string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Email     string     `gorm:"size:100" json:"email"` // 签发时的邮箱，邮箱变更后验证链接失效
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -16,11 +16,21 @@ func RegisterRoutes(r *gin.Engine) {
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// 公开订阅源（不需要认证）
+	// GET http://localhost:8080/feed.xml
+	registerFeedRoutes(r)
+
+	// 站点地图（不需要认证）
+	// GET http://localhost:8080/sitemap.xml - URL超过5万条时返回站点地图索引
+	r.GET("/sitemap.xml", controller.GetSitemap)
+	// GET http://localhost:8080/sitemaps/:name - 子站点地图，如 pages-1.xml、articles-1.xml
+	r.GET("/sitemaps/:name", controller.GetSitemapFile)
+
 	api := r.Group("/api")
 	{
 		// 认证相关接口（不需要JWT拦截器）
@@ -30,6 +40,50 @@ func RegisterRoutes(r *gin.Engine) {
 			auth.POST("/register", controller.Register)
 			// POST http://localhost:8080/api/auth/login
 			auth.POST("/login", controller.Login)
+			// POST http://localhost:8080/api/auth/refresh - 用刷新令牌换取新令牌，旧刷新令牌随即失效
+			auth.POST("/refresh", controller.RefreshToken)
+			// POST http://localhost:8080/api/auth/logout - 退出当前设备
+			auth.POST("/logout", middleware.AuthMiddleware(), controller.Logout)
+			// POST http://localhost:8080/api/auth/logout-all - 退出所有设备
+			auth.POST("/logout-all", middleware.AuthMiddleware(), controller.LogoutAll)
+			// POST http://localhost:8080/api/auth/2fa/verify - 登录第二步，提交挑战令牌和验证码（或恢复码）
+			auth.POST("/2fa/verify", controller.VerifyTwoFactorLogin)
+			// POST http://localhost:8080/api/auth/2fa/setup - 必须启用两步验证的账户在登录时绑定，再用 /2fa/verify 确认
+			auth.POST("/2fa/setup", controller.SetupTwoFactorForLogin)
+			// POST http://localhost:8080/api/auth/forgot-password - 发送重置密码邮件（按IP和邮箱限流，不暴露邮箱是否注册）
+			auth.POST("/forgot-password", controller.ForgotPassword)
+			// POST http://localhost:8080/api/auth/reset-password - 使用邮件中的一次性令牌设置新密码
+			auth.POST("/reset-password", controller.ResetPassword)
+			// GET http://localhost:8080/api/auth/verify-email?token= - 邮件中的验证链接（也可POST JSON）
+			auth.GET("/verify-email", controller.VerifyEmail)
+			auth.POST("/verify-email", controller.VerifyEmail)
+		}
+
+		// 公开只读接口（无需Token，携带有效Token时可识别当前用户，如liked_by_me）
+		public := api.Group("/public", middleware.OptionalAuthMiddleware())
+		{
+			// GET http://localhost:8080/api/public/article
+			public.GET("/article", controller.GetArticles)
+			// GET http://localhost:8080/api/public/article/pagination?keyword=go - 分页查询，支持关键词搜索和 tag/category 筛选
+			public.GET("/article/pagination", controller.GetArticlesWithPagination)
+			// GET http://localhost:8080/api/public/article/trending?window=24h|7d|30d|all&limit=10
+			public.GET("/article/trending", controller.GetTrendingArticles)
+			// GET http://localhost:8080/api/public/article/:id - 匿名访问只能查看已发布的文章
+			public.GET("/article/:id", controller.GetArticleByID)
+			// GET http://localhost:8080/api/public/article/slug/:slug
+			public.GET("/article/slug/:slug", controller.GetArticleBySlug)
+			// GET http://localhost:8080/api/public/article/:id/like - 点赞数（匿名访问时liked_by_me为false）
+			public.GET("/article/:id/like", controller.GetArticleLikes)
+			// GET http://localhost:8080/api/public/article/:id/comments
+			public.GET("/article/:id/comments", controller.GetArticleComments)
+			// GET http://localhost:8080/api/public/tags
+			public.GET("/tags", controller.GetTags)
+			// GET http://localhost:8080/api/public/categories
+			public.GET("/categories", controller.GetCategories)
+			// GET http://localhost:8080/api/public/users/:id/articles
+			public.GET("/users/:id/articles", controller.GetArticlesByAuthor)
+			// GET http://localhost:8080/api/public/feed.xml
+			registerFeedRoutes(public)
 		}
 
 		// 普通用户可访问的接口（只需要基础认证）
@@ -38,17 +92,39 @@ func RegisterRoutes(r *gin.Engine) {
 			// 文章查看相关接口
 			// GET http://localhost:8080/api/user/article
 			user.GET("/article", controller.GetArticles)
-			// GET http://localhost:8080/api/user/article/pagination - 支持可选关键词搜索
+			// GET http://localhost:8080/api/user/article/pagination - 支持可选关键词搜索和 tag/category 筛选
 			user.GET("/article/pagination", controller.GetArticlesWithPagination)
+			// GET http://localhost:8080/api/user/article/trending?window=24h|7d|30d|all&limit=10 - 热度榜（all为累计点赞榜）
+			user.GET("/article/trending", controller.GetTrendingArticles)
 			// GET http://localhost:8080/api/user/article/:id
 			user.GET("/article/:id", controller.GetArticleByID)
+			// GET http://localhost:8080/api/user/article/slug/:slug - 历史slug返回301重定向
+			user.GET("/article/slug/:slug", controller.GetArticleBySlug)
+
+			// 标签与分类接口
+			// GET http://localhost:8080/api/user/tags - 所有标签及文章数量
+			user.GET("/tags", controller.GetTags)
+			// GET http://localhost:8080/api/user/categories - 所有分类及文章数量
+			user.GET("/categories", controller.GetCategories)
 
 			// 文章点赞相关接口
-			// POST http://localhost:8080/api/user/article/:id/like
+			// POST http://localhost:8080/api/user/article/:id/like - 点赞（重复点赞不重复计数）
 			user.POST("/article/:id/like", controller.LikeArticle)
-			// GET http://localhost:8080/api/user/article/:id/like
+			// DELETE http://localhost:8080/api/user/article/:id/like - 取消点赞
+			user.DELETE("/article/:id/like", controller.UnlikeArticle)
+			// GET http://localhost:8080/api/user/article/:id/like - 点赞数及当前用户是否已点赞
 			user.GET("/article/:id/like", controller.GetArticleLikes)
 
+			// 文章评论接口
+			// GET http://localhost:8080/api/user/article/:id/comments - 分页获取评论树（按顶层评论分页）
+			user.GET("/article/:id/comments", controller.GetArticleComments)
+			// POST http://localhost:8080/api/user/article/:id/comments - 发表评论，parent_id不为空时为回复
+			user.POST("/article/:id/comments", controller.CreateComment)
+			// PUT http://localhost:8080/api/user/comment/:id - 编辑自己的评论
+			user.PUT("/comment/:id", controller.UpdateComment)
+			// DELETE http://localhost:8080/api/user/comment/:id - 删除自己的评论（管理员可删除任意评论）
+			user.DELETE("/comment/:id", controller.DeleteComment)
+
 			// 汇率查看接口
 			// GET http://localhost:8080/api/user/rate
 			user.GET("/rate", controller.GetExchangeRates)
@@ -60,19 +136,104 @@ func RegisterRoutes(r *gin.Engine) {
 			user.PUT("/profile", controller.UpdateMyProfile)
 			// PUT http://localhost:8080/api/user/password - 修改自己的密码
 			user.PUT("/password", controller.ChangeMyPassword)
+			// POST http://localhost:8080/api/user/email/verification - 重新发送邮箱验证邮件
+			user.POST("/email/verification", controller.SendVerificationEmail)
+			// GET http://localhost:8080/api/user/sessions - 查看自己的登录会话（设备、IP、最近活跃时间）
+			user.GET("/sessions", controller.GetMySessions)
+			// DELETE http://localhost:8080/api/user/sessions/:id - 结束指定会话（退出该设备）
+			user.DELETE("/sessions/:id", controller.DeleteMySession)
+
+			// 两步验证接口
+			// POST http://localhost:8080/api/user/2fa/setup - 生成密钥和otpauth://地址
+			user.POST("/2fa/setup", controller.SetupTwoFactor)
+			// POST http://localhost:8080/api/user/2fa/confirm - 用第一个验证码确认启用，返回恢复码
+			user.POST("/2fa/confirm", controller.ConfirmTwoFactor)
+			// POST http://localhost:8080/api/user/2fa/disable - 关闭两步验证（需密码和验证码）
+			user.POST("/2fa/disable", controller.DisableTwoFactor)
+			// POST http://localhost:8080/api/user/2fa/recovery-codes - 重新生成恢复码
+			user.POST("/2fa/recovery-codes", controller.RegenerateRecoveryCodes)
 			// GET http://localhost:8080/api/user/profile/:id - 查看指定用户资料（需要权限验证）
 			user.GET("/profile/:id", controller.GetUserProfile)
+			// GET http://localhost:8080/api/user/users/:id/articles - 分页查看指定作者的文章
+			user.GET("/users/:id/articles", controller.GetArticlesByAuthor)
+		}
+
+		// 文章编辑接口（作者或管理员，作者只能编辑自己的文章）
+		editor := api.Group("/admin", middleware.AuthorOrAdminMiddleware())
+		{
+			// POST http://localhost:8080/api/admin/article
+			editor.POST("/article", controller.CreateArticle)
+			// PUT http://localhost:8080/api/admin/article/:id - 更新文章（需携带If-Match）
+			editor.PUT("/article/:id", controller.UpdateArticle)
+			// PATCH http://localhost:8080/api/admin/article/:id - 部分更新文章（需携带If-Match）
+			editor.PATCH("/article/:id", controller.UpdateArticle)
+			// POST http://localhost:8080/api/admin/article/:id/transition - 文章状态流转（作者只能提交审核/撤回）
+			editor.POST("/article/:id/transition", controller.TransitionArticle)
+			// GET http://localhost:8080/api/admin/article/:id/transitions - 文章状态流转记录
+			editor.GET("/article/:id/transitions", controller.GetArticleStatusLogs)
+
+			// 修订历史接口
+			// GET http://localhost:8080/api/admin/article/:id/revisions - 修订历史列表
+			editor.GET("/article/:id/revisions", controller.GetArticleRevisions)
+			// GET http://localhost:8080/api/admin/article/:id/revisions/diff?from=1&to=3 - 对比两个版本
+			editor.GET("/article/:id/revisions/diff", controller.DiffArticleRevisions)
+			// GET http://localhost:8080/api/admin/article/:id/revisions/:version - 查看指定版本
+			editor.GET("/article/:id/revisions/:version", controller.GetArticleRevision)
+			// POST http://localhost:8080/api/admin/article/:id/revisions/:version/rollback - 回滚到指定版本
+			editor.POST("/article/:id/revisions/:version/rollback", controller.RollbackArticle)
 		}
 
 		// 管理员专用接口（需要管理员权限）
 		admin := api.Group("/admin", middleware.AdminOnlyMiddleware())
 		{
 			// 普通管理操作（使用JWT验证）
-			// POST http://localhost:8080/api/admin/article
-			admin.POST("/article", controller.CreateArticle)
 			// POST http://localhost:8080/api/admin/rate
 			admin.POST("/rate", controller.CreateExchangeRate)
 
+			// 回收站接口
+			// GET http://localhost:8080/api/admin/article/trash - 分页查询已软删除的文章
+			admin.GET("/article/trash", controller.GetTrashedArticles)
+			// POST http://localhost:8080/api/admin/article/trash/restore - 恢复文章
+			admin.POST("/article/trash/restore", controller.RestoreArticles)
+
+			// 导入导出接口（Markdown + YAML front matter，兼容Hexo/Hugo）
+			// POST http://localhost:8080/api/admin/article/import?dry_run=true - 上传zip导入，dry_run只返回冲突报告
+			admin.POST("/article/import", controller.ImportArticles)
+			// GET http://localhost:8080/api/admin/article/export?status=published - 导出为zip
+			admin.GET("/article/export", controller.ExportArticles)
+
+			// GET http://localhost:8080/api/admin/article/status?status=in_review - 按状态查询文章（审核队列）
+			admin.GET("/article/status", controller.GetArticlesByStatus)
+			// GET http://localhost:8080/api/admin/article/:id/stats?from=2025-01-01&to=2025-01-31 - 文章每日浏览量和独立访客数
+			admin.GET("/article/:id/stats", controller.GetArticleStats)
+
+			// 标签管理接口
+			// POST http://localhost:8080/api/admin/tag
+			admin.POST("/tag", controller.CreateTag)
+			// PUT http://localhost:8080/api/admin/tag/:id
+			admin.PUT("/tag/:id", controller.UpdateTag)
+			// DELETE http://localhost:8080/api/admin/tag/:id
+			admin.DELETE("/tag/:id", controller.DeleteTag)
+
+			// 分类管理接口
+			// POST http://localhost:8080/api/admin/category
+			admin.POST("/category", controller.CreateCategory)
+			// PUT http://localhost:8080/api/admin/category/:id
+			admin.PUT("/category/:id", controller.UpdateCategory)
+			// DELETE http://localhost:8080/api/admin/category/:id
+			admin.DELETE("/category/:id", controller.DeleteCategory)
+
+			// POST http://localhost:8080/api/admin/likes/reconcile - 对账Redis与MySQL中的点赞数据并报告差异
+			admin.POST("/likes/reconcile", controller.ReconcileLikes)
+
+			// 评论审核接口
+			// GET http://localhost:8080/api/admin/comments?status=pending - 按状态查询评论（审核队列）
+			admin.GET("/comments", controller.GetCommentsByStatus)
+			// POST http://localhost:8080/api/admin/comment/:id/approve - 审核通过评论
+			admin.POST("/comment/:id/approve", controller.ApproveComment)
+			// POST http://localhost:8080/api/admin/comment/:id/hide - 隐藏评论
+			admin.POST("/comment/:id/hide", controller.HideComment)
+
 			// 用户管理接口
 			// GET http://localhost:8080/api/admin/users - 获取所有用户列表
 			admin.GET("/users", controller.GetAllUsers)
@@ -80,6 +241,8 @@ func RegisterRoutes(r *gin.Engine) {
 			admin.GET("/user/:id", controller.GetUserProfile)
 			// PUT http://localhost:8080/api/admin/user/:id - 更新指定用户资料（包含角色和状态）
 			admin.PUT("/user/:id", controller.UpdateUserProfile)
+			// POST http://localhost:8080/api/admin/user/:id/unlock?ip= - 解除用户的登录锁定，可同时解除指定IP的封禁
+			admin.POST("/user/:id/unlock", controller.UnlockUserLogin)
 		}
 
 		// 敏感操作接口（需要数据库实时验证）
@@ -88,6 +251,8 @@ func RegisterRoutes(r *gin.Engine) {
 			// 敏感操作：删除数据（查询数据库验证权限）
 			// DELETE http://localhost:8080/api/admin/sensitive/article/batch
 			sensitive.DELETE("/article/batch", controller.BatchDeleteArticles)
+			// DELETE http://localhost:8080/api/admin/sensitive/article/trash - 从回收站彻底删除文章
+			sensitive.DELETE("/article/trash", controller.PurgeArticles)
 		}
 
 		// 保持向后兼容的业务接口（使用原有的全局中间件）
@@ -113,3 +278,21 @@ func RegisterRoutes(r *gin.Engine) {
 		}
 	}
 }
+
+// registerFeedRoutes 注册订阅源路由：全站、按标签、按作者，每种范围支持RSS、Atom和JSON Feed
+func registerFeedRoutes(r gin.IRoutes) {
+	// GET /feed.xml - RSS 2.0
+	r.GET("/feed.xml", controller.GetRSSFeed)
+	// GET /atom.xml - Atom
+	r.GET("/atom.xml", controller.GetAtomFeed)
+	// GET /feed.json - JSON Feed 1.1
+	r.GET("/feed.json", controller.GetJSONFeed)
+	// GET /tag/:name/feed.xml - 指定标签的订阅源（atom.xml、feed.json同理）
+	r.GET("/tag/:name/feed.xml", controller.GetRSSFeed)
+	r.GET("/tag/:name/atom.xml", controller.GetAtomFeed)
+	r.GET("/tag/:name/feed.json", controller.GetJSONFeed)
+	// GET /author/:id/feed.xml - 指定作者的订阅源（atom.xml、feed.json同理）
+	r.GET("/author/:id/feed.xml", controller.GetRSSFeed)
+	r.GET("/author/:id/atom.xml", controller.GetAtomFeed)
+	r.GET("/author/:id/feed.json", controller.GetJSONFeed)
+}
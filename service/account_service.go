@@ -0,0 +1,312 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 限流场景
+const (
+	rateScopeForgotIP    = "forgot_password:ip"
+	rateScopeForgotEmail = "forgot_password:email"
+	rateScopeResetIP     = "reset_password:ip"
+	rateScopeVerifyUser  = "verify_email:user"
+)
+
+// AccountService 邮箱验证和找回密码，邮件中的链接携带签名的一次性令牌
+type AccountService struct{}
+
+func NewAccountService() *AccountService {
+	return &AccountService{}
+}
+
+// SendVerificationEmail 向用户当前邮箱发送验证邮件
+func (s *AccountService) SendVerificationEmail(userID uint) error {
+	cfg := s.config()
+	if !s.allow(rateScopeVerifyUser, strconv.FormatUint(uint64(userID), 10), cfg.VerifyEmailPerUser) {
+		return fmt.Errorf("发送过于频繁，请稍后再试")
+	}
+
+	var user model.User
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("用户不存在")
+		}
+		return err
+	}
+	if user.Email == "" {
+		return fmt.Errorf("请先设置邮箱")
+	}
+	if user.EmailVerifiedAt != nil {
+		return fmt.Errorf("邮箱已验证")
+	}
+	return s.sendVerification(user)
+}
+
+// sendVerification 签发验证令牌并发送邮件，注册时也会调用
+func (s *AccountService) sendVerification(user model.User) error {
+	if err := mailerReady(); err != nil {
+		return err
+	}
+	expire := time.Duration(s.config().VerifyEmailExpireHours) * time.Hour
+	token, err := s.issueToken(user, global.TokenPurposeVerifyEmail, expire)
+	if err != nil {
+		return err
+	}
+
+	site := currentSiteConfig()
+	link := strings.TrimRight(site.BaseURL, "/") + "/api/auth/verify-email?token=" + url.QueryEscape(token)
+	sendMailAsync(utils.Mail{
+		To:      user.Email,
+		Subject: fmt.Sprintf("【%s】请验证你的邮箱", site.Title),
+		Body: fmt.Sprintf("%s，你好：\n\n请点击以下链接验证你的邮箱（%d小时内有效）：\n%s\n\n如果这不是你的操作，请忽略本邮件。\n",
+			user.Username, int(expire.Hours()), link),
+	})
+	return nil
+}
+
+// VerifyEmail 校验邮箱验证令牌并标记邮箱已验证；签发后邮箱被修改的令牌无效
+func (s *AccountService) VerifyEmail(rawToken string) error {
+	claims, err := utils.VerifySignedToken(rawToken, global.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		record, err := s.consumeToken(tx, claims)
+		if err != nil {
+			return err
+		}
+		result := tx.Model(&model.User{}).Where("id = ? AND email = ?", claims.UserID, record.Email).
+			Update("email_verified_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("链接无效或已过期")
+		}
+		return nil
+	})
+}
+
+// ForgotPassword 申请重置密码；无论邮箱是否注册都返回成功，避免被用来探测邮箱
+// 按IP限流返回错误；按邮箱限流时静默不发送，防止邮箱被刷
+func (s *AccountService) ForgotPassword(email, clientIP string) error {
+	cfg := s.config()
+	if !s.allow(rateScopeForgotIP, clientIP, cfg.ForgotPasswordPerIP) {
+		return fmt.Errorf("请求过于频繁，请稍后再试")
+	}
+	// 未配置邮件时直接报错，不依赖邮箱是否注册，不会暴露信息
+	if err := mailerReady(); err != nil {
+		return err
+	}
+	email = strings.TrimSpace(email)
+	if !s.allow(rateScopeForgotEmail, strings.ToLower(email), cfg.ForgotPasswordPerEmail) {
+		return nil
+	}
+
+	// 查询用户、生成令牌和发送都在后台进行，邮箱是否注册不影响响应时间
+	go s.sendResetPassword(email)
+	return nil
+}
+
+// sendResetPassword 邮箱属于正常状态的用户时生成重置令牌并发送邮件，失败只记录日志
+func (s *AccountService) sendResetPassword(email string) {
+	cfg := s.config()
+	var user model.User
+	if err := global.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Printf("查询重置密码用户失败: %v", err)
+		}
+		return
+	}
+	if user.Status != global.UserStatusActive {
+		return
+	}
+
+	expire := time.Duration(cfg.ResetPasswordExpireMinutes) * time.Minute
+	token, err := s.issueToken(user, global.TokenPurposeResetPassword, expire)
+	if err != nil {
+		log.Printf("生成重置密码令牌失败: %v", err)
+		return
+	}
+
+	site := currentSiteConfig()
+	resetURL := cfg.ResetPasswordURL
+	if resetURL == "" {
+		resetURL = strings.TrimRight(site.BaseURL, "/") + "/reset-password"
+	}
+	mail := utils.Mail{
+		To:      user.Email,
+		Subject: fmt.Sprintf("【%s】重置密码", site.Title),
+		Body: fmt.Sprintf("%s，你好：\n\n我们收到了重置密码的申请，请点击以下链接设置新密码（%d分钟内有效，只能使用一次）：\n%s?token=%s\n\n如果这不是你的操作，请忽略本邮件，你的密码不会改变。\n",
+			user.Username, int(expire.Minutes()), resetURL, url.QueryEscape(token)),
+	}
+	if err := mailer.Send(mail); err != nil {
+		log.Printf("发送邮件到%s失败: %v", mail.To, err)
+	}
+}
+
+// ResetPassword 使用重置令牌设置新密码，成功后注销所有登录状态并解除登录锁定
+func (s *AccountService) ResetPassword(req dto.ResetPasswordRequest, clientIP string) error {
+	if !s.allow(rateScopeResetIP, clientIP, s.config().ResetPasswordPerIP) {
+		return fmt.Errorf("请求过于频繁，请稍后再试")
+	}
+	claims, err := utils.VerifySignedToken(req.Token, global.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("密码加密失败")
+	}
+
+	var user model.User
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		record, err := s.consumeToken(tx, claims)
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&user, claims.UserID).Error; err != nil {
+			return fmt.Errorf("链接无效或已过期")
+		}
+		// 发出邮件后邮箱已被修改的，旧邮箱收到的链接作废
+		if !strings.EqualFold(user.Email, record.Email) {
+			return fmt.Errorf("链接无效或已过期")
+		}
+		if user.Status == global.UserStatusDisabled {
+			return fmt.Errorf("用户账户已被禁用")
+		}
+
+		updates := map[string]interface{}{"password": hashedPassword}
+		// 能收到重置邮件说明邮箱属于该用户
+		if user.EmailVerifiedAt == nil {
+			updates["email_verified_at"] = time.Now()
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("密码更新失败: %v", err)
+		}
+
+		// 同一用户其余未使用的重置令牌一并作废
+		return tx.Model(&model.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, global.TokenPurposeResetPassword).
+			Update("used_at", time.Now()).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := NewTokenService().RevokeAllSessions(user.ID); err != nil {
+		log.Printf("注销用户%d的登录状态失败: %v", user.ID, err)
+	}
+	NewLoginGuardService().Reset(user.Username)
+	return nil
+}
+
+// issueToken 登记一次性令牌并签名；同一用途之前未使用的令牌作废，只有最新的链接有效
+func (s *AccountService) issueToken(user model.User, purpose string, expire time.Duration) (string, error) {
+	nonce, err := utils.RandomToken(16)
+	if err != nil {
+		return "", fmt.Errorf("生成令牌失败")
+	}
+	record := model.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Nonce:     nonce,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(expire),
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND (used_at IS NULL OR expires_at < ?)", user.ID, purpose, time.Now()).
+			Delete(&model.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成令牌失败: %v", err)
+	}
+
+	return utils.SignToken(utils.SignedToken{
+		Purpose:   purpose,
+		UserID:    user.ID,
+		Nonce:     nonce,
+		ExpiresAt: record.ExpiresAt,
+	})
+}
+
+// consumeToken 将令牌标记为已使用，并发请求中只有一个能成功
+func (s *AccountService) consumeToken(tx *gorm.DB, claims *utils.SignedToken) (*model.UserToken, error) {
+	var record model.UserToken
+	if err := tx.Where("nonce = ? AND user_id = ? AND purpose = ?", claims.Nonce, claims.UserID, claims.Purpose).
+		First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("链接无效或已过期")
+		}
+		return nil, err
+	}
+
+	result := tx.Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", record.ID, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("链接无效或已过期")
+	}
+	return &record, nil
+}
+
+// allow 限流检查，Redis不可用时放行
+func (s *AccountService) allow(scope, key string, limit int) bool {
+	window := time.Duration(s.config().RateLimitWindowMinutes) * time.Minute
+	ok, err := utils.AllowRequest(scope, key, limit, window)
+	if err != nil {
+		log.Printf("限流计数失败: %v", err)
+	}
+	return ok
+}
+
+// config 账户安全配置，未配置的项使用默认值
+func (s *AccountService) config() config.AccountConfig {
+	cfg := config.AccountConfig{}
+	if c := config.GetAccountConfig(); c != nil {
+		cfg = *c
+	}
+	if cfg.VerifyEmailExpireHours <= 0 {
+		cfg.VerifyEmailExpireHours = 24
+	}
+	if cfg.ResetPasswordExpireMinutes <= 0 {
+		cfg.ResetPasswordExpireMinutes = 30
+	}
+	if cfg.RateLimitWindowMinutes <= 0 {
+		cfg.RateLimitWindowMinutes = 60
+	}
+	if cfg.ForgotPasswordPerIP <= 0 {
+		cfg.ForgotPasswordPerIP = 5
+	}
+	if cfg.ForgotPasswordPerEmail <= 0 {
+		cfg.ForgotPasswordPerEmail = 3
+	}
+	if cfg.ResetPasswordPerIP <= 0 {
+		cfg.ResetPasswordPerIP = 10
+	}
+	if cfg.VerifyEmailPerUser <= 0 {
+		cfg.VerifyEmailPerUser = 3
+	}
+	return cfg
+}
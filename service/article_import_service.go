@@ -0,0 +1,399 @@
+package service
+
+import (
+	"archive/zip"
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const (
+	importMaxFiles    = 5000              // 单个压缩包最多导入的文件数
+	importMaxFileSize = 5 * 1024 * 1024   // 单个Markdown文件的最大解压大小
+	importMaxSize     = 100 * 1024 * 1024 // 整个压缩包的最大解压总量，解析后的正文在写入前都保存在内存中
+)
+
+// 导入结果
+const (
+	ImportActionCreate   = "create"
+	ImportActionConflict = "conflict"
+	ImportActionInvalid  = "invalid"
+)
+
+// articleFrontMatter Markdown文件的front matter，兼容Hexo/Hugo常用字段
+// 导出时写出的字段都能被导入读取，保证往返无损
+type articleFrontMatter struct {
+	Title       string                `yaml:"title"`
+	Slug        string                `yaml:"slug,omitempty"`
+	Date        utils.FrontMatterTime `yaml:"date,omitempty"`       // 创建时间
+	Updated     utils.FrontMatterTime `yaml:"updated,omitempty"`    // 更新时间，Hugo为lastmod
+	LastMod     utils.FrontMatterTime `yaml:"lastmod,omitempty"`    // 仅导入
+	Published   utils.FrontMatterTime `yaml:"published,omitempty"`  // 首次发布时间，为空时已发布文章取date
+	PublishAt   utils.FrontMatterTime `yaml:"publish_at,omitempty"` // 定时发布时间
+	Draft       bool                  `yaml:"draft,omitempty"`
+	Status      string                `yaml:"status,omitempty"` // 草稿和已发布以外的状态
+	Author      string                `yaml:"author,omitempty"` // 作者用户名，不存在时归属导入人
+	Categories  utils.StringList      `yaml:"categories,omitempty"`
+	Tags        utils.StringList      `yaml:"tags,omitempty"`
+	Description string                `yaml:"description,omitempty"` // 文章摘要
+	Summary     string                `yaml:"summary,omitempty"`     // 仅导入，Hugo的摘要字段
+}
+
+// importDocument 解析后待导入的文章
+type importDocument struct {
+	item        *dto.ArticleImportItem
+	frontMatter articleFrontMatter
+	content     string
+	status      string
+}
+
+// ImportArticles 从zip压缩包导入带front matter的Markdown文章
+// slug已被占用或在压缩包内重复时跳过并记录冲突；dryRun为true时只生成报告，不写入数据库
+func (s *ArticleService) ImportArticles(r io.ReaderAt, size int64, operatorID uint, dryRun bool) (*dto.ArticleImportReport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("无效的zip文件: %v", err)
+	}
+
+	report := &dto.ArticleImportReport{DryRun: dryRun, Items: make([]dto.ArticleImportItem, 0)}
+	docs := make([]importDocument, 0)
+	var totalSize int
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !isMarkdownFile(file.Name) {
+			continue
+		}
+		if len(report.Items) >= importMaxFiles {
+			return nil, fmt.Errorf("压缩包内文件过多，最多%d个", importMaxFiles)
+		}
+
+		report.Items = append(report.Items, dto.ArticleImportItem{File: file.Name})
+		item := &report.Items[len(report.Items)-1]
+		doc, err := s.parseImportFile(file)
+		if err != nil {
+			item.Action = ImportActionInvalid
+			item.Reason = err.Error()
+			continue
+		}
+		totalSize += len(doc.content)
+		if totalSize > importMaxSize {
+			return nil, fmt.Errorf("压缩包解压后超过%dMB", importMaxSize/1024/1024)
+		}
+		item.Title = doc.frontMatter.Title
+		doc.item = item
+		docs = append(docs, doc)
+	}
+
+	// 检查slug冲突：已被其他文章占用（包括历史slug）或在压缩包内重复
+	seen := make(map[string]string)
+	for i := range docs {
+		item := docs[i].item
+		if docs[i].frontMatter.Slug == "" {
+			item.Action = ImportActionCreate
+			continue
+		}
+		slug := utils.Slugify(docs[i].frontMatter.Slug)
+		if slug == "" {
+			item.Action = ImportActionInvalid
+			item.Reason = "slug格式无效，只能包含字母、数字和汉字"
+			continue
+		}
+		item.Slug = slug
+
+		if file, ok := seen[slug]; ok {
+			item.Action = ImportActionConflict
+			item.Reason = fmt.Sprintf("slug与压缩包内的%s重复", file)
+			continue
+		}
+		seen[slug] = item.File
+
+		var owner model.ArticleSlug
+		err := global.DB.Where("slug = ?", slug).First(&owner).Error
+		if err == nil {
+			item.Action = ImportActionConflict
+			item.Reason = fmt.Sprintf("slug已被文章%d使用", owner.ArticleID)
+			continue
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+		item.Action = ImportActionCreate
+	}
+
+	createdIDs := make([]uint, 0)
+	for i := range docs {
+		item := docs[i].item
+		if item.Action != ImportActionCreate || dryRun {
+			continue
+		}
+		article, err := s.createImportedArticle(docs[i], operatorID)
+		if err != nil {
+			item.Action = ImportActionInvalid
+			item.Reason = err.Error()
+			continue
+		}
+		item.ArticleID = article.ID
+		item.Slug = article.Slug
+		createdIDs = append(createdIDs, article.ID)
+		indexArticle(*article)
+	}
+
+	report.Total = len(report.Items)
+	for _, item := range report.Items {
+		switch item.Action {
+		case ImportActionCreate:
+			report.Created++
+		case ImportActionConflict:
+			report.Conflicts++
+		case ImportActionInvalid:
+			report.Invalid++
+		}
+	}
+
+	if len(createdIDs) > 0 {
+		s.clearAllCache()
+		NewSitemapService().RefreshArticles(createdIDs...)
+	}
+	return report, nil
+}
+
+// parseImportFile 读取并解析压缩包中的Markdown文件
+func (s *ArticleService) parseImportFile(file *zip.File) (importDocument, error) {
+	if file.UncompressedSize64 > importMaxFileSize {
+		return importDocument{}, fmt.Errorf("文件超过%dMB", importMaxFileSize/1024/1024)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return importDocument{}, fmt.Errorf("读取文件失败: %v", err)
+	}
+	defer rc.Close()
+
+	// 不信任压缩包中记录的大小，按上限截断读取
+	src, err := io.ReadAll(io.LimitReader(rc, importMaxFileSize+1))
+	if err != nil {
+		return importDocument{}, fmt.Errorf("读取文件失败: %v", err)
+	}
+	if len(src) > importMaxFileSize {
+		return importDocument{}, fmt.Errorf("文件超过%dMB", importMaxFileSize/1024/1024)
+	}
+
+	rawFrontMatter, body, err := utils.SplitFrontMatter(src)
+	if err != nil {
+		return importDocument{}, err
+	}
+	var fm articleFrontMatter
+	if err := yaml.Unmarshal(rawFrontMatter, &fm); err != nil {
+		return importDocument{}, fmt.Errorf("解析front matter失败: %v", err)
+	}
+	fm.Title = strings.TrimSpace(fm.Title)
+	if fm.Title == "" {
+		return importDocument{}, fmt.Errorf("缺少标题")
+	}
+
+	status := global.ArticleStatusPublished
+	switch {
+	case fm.Status != "":
+		status = fm.Status
+	case fm.Draft:
+		status = global.ArticleStatusDraft
+	}
+	switch status {
+	case global.ArticleStatusDraft, global.ArticleStatusInReview, global.ArticleStatusPublished, global.ArticleStatusArchived:
+	case global.ArticleStatusScheduled:
+		if fm.PublishAt.IsZero() {
+			return importDocument{}, fmt.Errorf("定时发布的文章缺少publish_at")
+		}
+	default:
+		return importDocument{}, fmt.Errorf("无效的文章状态: %s", status)
+	}
+
+	return importDocument{frontMatter: fm, content: string(body), status: status}, nil
+}
+
+// createImportedArticle 在事务中创建导入的文章，保留原有的创建、更新和发布时间
+func (s *ArticleService) createImportedArticle(doc importDocument, operatorID uint) (*model.Article, error) {
+	fm := doc.frontMatter
+
+	contentHTML, contentTOC, err := s.renderContent(doc.content)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	created := fm.Date.Time
+	if created.IsZero() {
+		created = now
+	}
+	updated := fm.Updated.Time
+	if updated.IsZero() {
+		updated = fm.LastMod.Time
+	}
+	if updated.IsZero() {
+		updated = created
+	}
+
+	preview := fm.Description
+	if preview == "" {
+		preview = fm.Summary
+	}
+
+	article := model.Article{
+		Title:       fm.Title,
+		Content:     doc.content,
+		Preview:     preview,
+		ContentHTML: contentHTML,
+		ContentTOC:  contentTOC,
+		AuthorID:    &operatorID,
+		Status:      doc.status,
+	}
+	article.CreatedAt = created
+	article.UpdatedAt = updated
+	if !fm.Published.IsZero() {
+		published := fm.Published.Time
+		article.PublishedAt = &published
+	} else if doc.status == global.ArticleStatusPublished || doc.status == global.ArticleStatusArchived {
+		article.PublishedAt = &created
+	}
+	if doc.status == global.ArticleStatusScheduled {
+		publishAt := fm.PublishAt.Time
+		article.PublishAt = &publishAt
+	}
+
+	if fm.Author != "" {
+		var author model.User
+		if err := global.DB.Select("id").Where("username = ?", fm.Author).First(&author).Error; err == nil {
+			article.AuthorID = &author.ID
+		}
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		// 本系统每篇文章只有一个分类，取第一个
+		if len(fm.Categories) > 0 {
+			category := model.Category{Name: fm.Categories[0]}
+			if err := tx.Where("name = ?", category.Name).FirstOrCreate(&category).Error; err != nil {
+				return fmt.Errorf("保存分类失败: %v", err)
+			}
+			article.CategoryID = &category.ID
+		}
+
+		tags, err := findOrCreateTags(tx, fm.Tags)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+
+		if err := tx.Create(&article).Error; err != nil {
+			return err
+		}
+		if err := s.assignSlug(tx, &article, fm.Slug); err != nil {
+			return err
+		}
+		// 写入slug会刷新更新时间，恢复为导入的时间
+		if err := tx.Model(&model.Article{}).Where("id = ?", article.ID).UpdateColumn("updated_at", updated).Error; err != nil {
+			return err
+		}
+		return s.createRevision(tx, article, operatorID, "导入文章")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("保存文章失败: %v", err)
+	}
+	return &article, nil
+}
+
+// ExportArticles 将未删除的文章导出为zip压缩包，每篇文章一个带front matter的Markdown文件
+// status不为空时只导出该状态的文章，返回导出数量
+func (s *ArticleService) ExportArticles(w io.Writer, status string) (int, error) {
+	archive := zip.NewWriter(w)
+
+	query := s.withRelations(global.DB).Order("id ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	total := 0
+	var articles []model.Article
+	err := query.FindInBatches(&articles, 200, func(tx *gorm.DB, batch int) error {
+		for _, a := range articles {
+			content, err := s.exportArticle(a)
+			if err != nil {
+				return err
+			}
+
+			name := a.Slug
+			if name == "" {
+				name = fmt.Sprintf("article-%d", a.ID)
+			}
+			header := &zip.FileHeader{Name: name + ".md", Method: zip.Deflate, Modified: a.UpdatedAt}
+			fw, err := archive.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			if _, err := fw.Write(content); err != nil {
+				return err
+			}
+		}
+		total += len(articles)
+		return nil
+	}).Error
+	if err != nil {
+		return 0, fmt.Errorf("导出文章失败: %v", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return 0, fmt.Errorf("导出文章失败: %v", err)
+	}
+	return total, nil
+}
+
+// exportArticle 生成单篇文章的Markdown文件
+func (s *ArticleService) exportArticle(a model.Article) ([]byte, error) {
+	fm := articleFrontMatter{
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Date:        utils.FrontMatterTime{Time: a.CreatedAt},
+		Updated:     utils.FrontMatterTime{Time: a.UpdatedAt},
+		Draft:       a.Status == global.ArticleStatusDraft,
+		Description: a.Preview,
+	}
+	if a.Status != global.ArticleStatusDraft && a.Status != global.ArticleStatusPublished {
+		fm.Status = a.Status
+	}
+	if a.PublishedAt != nil {
+		fm.Published = utils.FrontMatterTime{Time: *a.PublishedAt}
+	}
+	if a.PublishAt != nil && a.Status == global.ArticleStatusScheduled {
+		fm.PublishAt = utils.FrontMatterTime{Time: *a.PublishAt}
+	}
+	if a.Author != nil {
+		fm.Author = a.Author.Username
+	}
+	if a.Category != nil {
+		fm.Categories = utils.StringList{a.Category.Name}
+	}
+	for _, tag := range a.Tags {
+		fm.Tags = append(fm.Tags, tag.Name)
+	}
+
+	rawFrontMatter, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+	return utils.JoinFrontMatter(rawFrontMatter, []byte(a.Content)), nil
+}
+
+// isMarkdownFile 判断是否为需要导入的Markdown文件，忽略macOS生成的元数据文件
+func isMarkdownFile(name string) bool {
+	if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
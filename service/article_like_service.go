@@ -2,41 +2,523 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"go_test/dto"
 	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+	"strconv"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
 )
 
 var likeCtxRedis = context.Background()
 
+// legacyLikersPattern 旧版本点赞用户集合的键（未使用统一前缀），重建时迁移到新键
+const legacyLikersPattern = "article:*:likers"
+
+// legacyLikeCounterPattern 最早版本的匿名点赞计数键 article:<文章ID>:likes，启动时迁移到文章的legacy_likes字段
+const legacyLikeCounterPattern = "article:*:likes"
+
 type ArticleLikeService struct{}
 
 func NewArticleLikeService() *ArticleLikeService {
 	return &ArticleLikeService{}
 }
 
-// LikeArticle 给文章点赞业务逻辑
-func (s *ArticleLikeService) LikeArticle(articleID string) error {
-	likeKey := "article:" + articleID + ":likes"
+// LikeArticle 给文章点赞业务逻辑，同一用户重复点赞不会重复计数
+// 点赞只写Redis并标记待回写，由LikeSyncWorker批量同步到MySQL
+func (s *ArticleLikeService) LikeArticle(articleID, userID uint) (int64, error) {
+	legacyLikes, err := s.checkArticleExists(articleID)
+	if err != nil {
+		return 0, err
+	}
 
-	if err := global.RedisDB.Incr(likeCtxRedis, likeKey).Err(); err != nil {
-		return err
+	likersKey := s.likersKey(articleID)
+	var addCmd, countCmd *redis.IntCmd
+	_, err = global.RedisDB.TxPipelined(likeCtxRedis, func(pipe redis.Pipeliner) error {
+		addCmd = pipe.SAdd(likeCtxRedis, likersKey, userID)
+		pipe.SAdd(likeCtxRedis, global.CacheKeyArticleLikesDirty, articleID)
+		countCmd = pipe.SCard(likeCtxRedis, likersKey)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 只有新增点赞才计入排行榜
+	if addCmd.Val() > 0 {
+		s.updateLeaderboards(articleID, 1)
+	}
+
+	return countCmd.Val() + legacyLikes, nil
+}
+
+// UnlikeArticle 取消点赞业务逻辑，未点赞时直接返回当前点赞数
+func (s *ArticleLikeService) UnlikeArticle(articleID, userID uint) (int64, error) {
+	legacyLikes, err := s.checkArticleExists(articleID)
+	if err != nil {
+		return 0, err
+	}
+
+	likersKey := s.likersKey(articleID)
+	var remCmd, countCmd *redis.IntCmd
+	_, err = global.RedisDB.TxPipelined(likeCtxRedis, func(pipe redis.Pipeliner) error {
+		remCmd = pipe.SRem(likeCtxRedis, likersKey, userID)
+		pipe.SAdd(likeCtxRedis, global.CacheKeyArticleLikesDirty, articleID)
+		countCmd = pipe.SCard(likeCtxRedis, likersKey)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if remCmd.Val() > 0 {
+		s.updateLeaderboards(articleID, -1)
 	}
 
+	return countCmd.Val() + legacyLikes, nil
+}
+
+// updateLeaderboards 点赞变化时更新热度榜和累计点赞榜，失败只记录日志
+// 累计点赞榜在回写MySQL时会按实际点赞数校正
+func (s *ArticleLikeService) updateLeaderboards(articleID uint, delta int) {
+	if err := bumpTrendingScore(likeCtxRedis, articleID, trendingLikeScore*float64(delta)); err != nil {
+		log.Printf("更新文章%d热度失败: %v", articleID, err)
+	}
+	member := strconv.FormatUint(uint64(articleID), 10)
+	if err := global.RedisDB.ZIncrBy(likeCtxRedis, global.CacheKeyLeaderboardLike, float64(delta), member).Err(); err != nil {
+		log.Printf("更新文章%d点赞榜失败: %v", articleID, err)
+	}
+}
+
+// GetArticleLikes 获取文章点赞数量以及当前用户是否已点赞
+func (s *ArticleLikeService) GetArticleLikes(articleID, userID uint) (int64, bool, error) {
+	legacyLikes, err := s.checkArticleExists(articleID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	likersKey := s.likersKey(articleID)
+	pipe := global.RedisDB.Pipeline()
+	countCmd := pipe.SCard(likeCtxRedis, likersKey)
+	likedCmd := pipe.SIsMember(likeCtxRedis, likersKey, userID)
+	if _, err := pipe.Exec(likeCtxRedis); err != nil {
+		return 0, false, err
+	}
+
+	return countCmd.Val() + legacyLikes, likedCmd.Val(), nil
+}
+
+// EnsureLikesLoaded 检查Redis中的点赞数据是否已加载，缺失时（首次启动或Redis被清空）从MySQL重建
+// 返回false表示其他实例正在重建，本次不应回写，避免用不完整的Redis数据覆盖MySQL
+func (s *ArticleLikeService) EnsureLikesLoaded(ctx context.Context, lockTTL time.Duration) (bool, error) {
+	loaded, err := global.RedisDB.Exists(ctx, global.CacheKeyArticleLikesLoaded).Result()
+	if err != nil {
+		return false, err
+	}
+	if loaded > 0 {
+		return true, nil
+	}
+
+	token, ok, err := utils.AcquireLock(ctx, global.CacheKeyLockLikeRebuild, lockTTL)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer func() {
+		if err := utils.ReleaseLock(context.Background(), global.CacheKeyLockLikeRebuild, token); err != nil {
+			log.Printf("释放点赞重建锁失败: %v", err)
+		}
+	}()
+
+	// 获取锁后再次检查，可能其他实例刚完成重建
+	loaded, err = global.RedisDB.Exists(ctx, global.CacheKeyArticleLikesLoaded).Result()
+	if err != nil {
+		return false, err
+	}
+	if loaded > 0 {
+		return true, nil
+	}
+
+	if err := s.rebuildLikesFromDB(ctx); err != nil {
+		return false, err
+	}
+	if err := s.migrateLegacyLikers(ctx); err != nil {
+		return false, err
+	}
+	if err := global.RedisDB.Set(ctx, global.CacheKeyArticleLikesLoaded, time.Now().Unix(), 0).Err(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rebuildLikesFromDB 将MySQL中的点赞记录加载到Redis，与Redis中已有的数据合并
+func (s *ArticleLikeService) rebuildLikesFromDB(ctx context.Context) error {
+	var likes []model.ArticleLike
+	total := 0
+	err := global.DB.Model(&model.ArticleLike{}).FindInBatches(&likes, 1000, func(tx *gorm.DB, batch int) error {
+		pipe := global.RedisDB.Pipeline()
+		for _, like := range likes {
+			pipe.SAdd(ctx, s.likersKey(like.ArticleID), like.UserID)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return err
+		}
+		total += len(likes)
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("从MySQL重建点赞数据失败: %v", err)
+	}
+
+	// 以MySQL中的点赞数重建累计点赞榜
+	var articles []model.Article
+	if err := global.DB.Select("id", "like_count").Where("like_count > 0").Find(&articles).Error; err != nil {
+		return fmt.Errorf("重建点赞榜失败: %v", err)
+	}
+	if len(articles) > 0 {
+		members := make([]*redis.Z, 0, len(articles))
+		for _, a := range articles {
+			members = append(members, &redis.Z{Score: float64(a.LikeCount), Member: a.ID})
+		}
+		if err := global.RedisDB.ZAdd(ctx, global.CacheKeyLeaderboardLike, members...).Err(); err != nil {
+			return fmt.Errorf("重建点赞榜失败: %v", err)
+		}
+	}
+
+	log.Printf("已从MySQL重建%d条点赞记录", total)
 	return nil
 }
 
-// GetArticleLikes 获取文章点赞数量业务逻辑
-func (s *ArticleLikeService) GetArticleLikes(articleID string) (string, error) {
-	likeKey := "article:" + articleID + ":likes"
+// migrateLegacyLikers 将旧键中的点赞用户合并到新键，并标记待回写
+func (s *ArticleLikeService) migrateLegacyLikers(ctx context.Context) error {
+	iter := global.RedisDB.Scan(ctx, 0, legacyLikersPattern, 100).Iterator()
+	for iter.Next(ctx) {
+		legacyKey := iter.Val()
+		var articleID uint
+		if _, err := fmt.Sscanf(legacyKey, "article:%d:likers", &articleID); err != nil {
+			continue
+		}
+
+		likersKey := s.likersKey(articleID)
+		_, err := global.RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SUnionStore(ctx, likersKey, likersKey, legacyKey)
+			pipe.SAdd(ctx, global.CacheKeyArticleLikesDirty, articleID)
+			pipe.Del(ctx, legacyKey)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("迁移旧点赞数据失败: %v", err)
+		}
+	}
+	return iter.Err()
+}
 
-	likes, err := global.RedisDB.Get(likeCtxRedis, likeKey).Result()
+// MigrateLegacyLikeCounters 将最早版本的匿名点赞计数迁移到文章的legacy_likes字段，启动时执行一次
+// 旧计数没有记录点赞用户，只能作为额外的点赞数保留；写入是覆盖而非累加，中途失败重复执行也不会重复计数
+func (s *ArticleLikeService) MigrateLegacyLikeCounters(ctx context.Context) error {
+	migrated := 0
+	iter := global.RedisDB.Scan(ctx, 0, legacyLikeCounterPattern, 100).Iterator()
+	for iter.Next(ctx) {
+		legacyKey := iter.Val()
+		var articleID uint
+		if _, err := fmt.Sscanf(legacyKey, "article:%d:likes", &articleID); err != nil {
+			continue
+		}
+		value, err := global.RedisDB.Get(ctx, legacyKey).Int64()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("读取旧点赞计数%s失败: %v", legacyKey, err)
+			continue
+		}
 
-	if err == redis.Nil {
-		return "0", nil
-	} else if err != nil {
-		return "", err
+		if err := global.DB.Unscoped().Model(&model.Article{}).Where("id = ?", articleID).
+			UpdateColumn("legacy_likes", value).Error; err != nil {
+			return fmt.Errorf("迁移旧点赞计数失败: %v", err)
+		}
+		// 标记待回写，由回写任务重新计算点赞数和点赞榜
+		_, err = global.RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SAdd(ctx, global.CacheKeyArticleLikesDirty, articleID)
+			pipe.Del(ctx, legacyKey)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("迁移旧点赞计数失败: %v", err)
+		}
+		migrated++
 	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if migrated > 0 {
+		log.Printf("已迁移%d篇文章的旧点赞计数", migrated)
+	}
+	return nil
+}
+
+// FlushDirtyLikes 将有变更的文章点赞数据按批回写MySQL，返回回写的文章数量
+// 回写失败的文章会重新标记，下次继续回写
+func (s *ArticleLikeService) FlushDirtyLikes(ctx context.Context, batchSize int) (int, error) {
+	flushed := 0
+	for {
+		members, err := global.RedisDB.SPopN(ctx, global.CacheKeyArticleLikesDirty, int64(batchSize)).Result()
+		if err != nil && err != redis.Nil {
+			return flushed, err
+		}
+		if len(members) == 0 {
+			return flushed, nil
+		}
+
+		for i, member := range members {
+			articleID, err := strconv.ParseUint(member, 10, 32)
+			if err != nil {
+				continue
+			}
+			if err := s.syncArticleLikes(ctx, uint(articleID)); err != nil {
+				// 当前及剩余未处理的文章重新标记为待回写
+				pending := make([]interface{}, 0, len(members)-i)
+				for _, m := range members[i:] {
+					pending = append(pending, m)
+				}
+				if err := global.RedisDB.SAdd(ctx, global.CacheKeyArticleLikesDirty, pending...).Err(); err != nil {
+					log.Printf("重新标记待回写文章失败: %v", err)
+				}
+				return flushed, err
+			}
+			flushed++
+		}
+
+		if len(members) < batchSize {
+			return flushed, nil
+		}
+	}
+}
+
+// syncArticleLikes 以Redis中的点赞用户集合为准，同步单篇文章的点赞记录和点赞数到MySQL
+func (s *ArticleLikeService) syncArticleLikes(ctx context.Context, articleID uint) error {
+	redisLikers, err := s.redisLikers(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	// 文章已被彻底删除时清理残留数据
+	var article model.Article
+	err = global.DB.Unscoped().Select("id", "legacy_likes").Where("id = ?", articleID).First(&article).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err == gorm.ErrRecordNotFound {
+		if err := global.DB.Where("article_id = ?", articleID).Delete(&model.ArticleLike{}).Error; err != nil {
+			return err
+		}
+		if err := global.RedisDB.ZRem(ctx, global.CacheKeyLeaderboardLike, articleID).Err(); err != nil {
+			return err
+		}
+		return global.RedisDB.Del(ctx, s.likersKey(articleID)).Err()
+	}
+
+	likeCount := int64(len(redisLikers)) + article.LegacyLikes
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		dbLikers, err := s.dbLikers(tx, articleID)
+		if err != nil {
+			return err
+		}
+
+		toInsert := make([]model.ArticleLike, 0)
+		for userID := range redisLikers {
+			if !dbLikers[userID] {
+				toInsert = append(toInsert, model.ArticleLike{ArticleID: articleID, UserID: userID})
+			}
+		}
+		toDelete := make([]uint, 0)
+		for userID := range dbLikers {
+			if !redisLikers[userID] {
+				toDelete = append(toDelete, userID)
+			}
+		}
+
+		if len(toInsert) > 0 {
+			if err := tx.CreateInBatches(&toInsert, 500).Error; err != nil {
+				return fmt.Errorf("写入点赞记录失败: %v", err)
+			}
+		}
+		if len(toDelete) > 0 {
+			if err := tx.Where("article_id = ? AND user_id IN ?", articleID, toDelete).Delete(&model.ArticleLike{}).Error; err != nil {
+				return fmt.Errorf("删除点赞记录失败: %v", err)
+			}
+		}
+
+		return tx.Unscoped().Model(&model.Article{}).
+			Where("id = ?", articleID).
+			UpdateColumn("like_count", likeCount).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 以实际点赞数校正累计点赞榜
+	return global.RedisDB.ZAdd(ctx, global.CacheKeyLeaderboardLike, &redis.Z{Score: float64(likeCount), Member: articleID}).Err()
+}
+
+// ReconcileLikes 先回写所有待回写的数据，再逐篇对比Redis与MySQL的点赞记录并报告差异
+// Redis数据完整时以Redis为准修复MySQL，避免已在Redis取消但尚未回写的点赞被MySQL中的旧记录恢复；
+// 只有Redis数据丢失（加载标记缺失）时才按并集修复，把MySQL独有的点赞补回Redis
+func (s *ArticleLikeService) ReconcileLikes(ctx context.Context) (map[string]interface{}, error) {
+	marker, err := global.RedisDB.Exists(ctx, global.CacheKeyArticleLikesLoaded).Result()
+	if err != nil {
+		return nil, err
+	}
+	redisLost := marker == 0
+
+	loaded, err := s.EnsureLikesLoaded(ctx, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if !loaded {
+		return nil, fmt.Errorf("点赞数据正在重建，请稍后重试")
+	}
+
+	if _, err := s.FlushDirtyLikes(ctx, 100); err != nil {
+		return nil, fmt.Errorf("回写点赞数据失败: %v", err)
+	}
+
+	// 收集两个存储中所有有点赞的文章
+	articleIDs := make(map[uint]bool)
+	iter := global.RedisDB.Scan(ctx, 0, global.CacheKeyArticleLikes+":*", 100).Iterator()
+	for iter.Next(ctx) {
+		var articleID uint
+		if _, err := fmt.Sscanf(iter.Val(), global.CacheKeyArticleLikes+":%d", &articleID); err == nil {
+			articleIDs[articleID] = true
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	var dbArticleIDs []uint
+	if err := global.DB.Model(&model.ArticleLike{}).Distinct().Pluck("article_id", &dbArticleIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range dbArticleIDs {
+		articleIDs[id] = true
+	}
+
+	drifts := make([]dto.LikeDriftVO, 0)
+	for articleID := range articleIDs {
+		redisLikers, err := s.redisLikers(ctx, articleID)
+		if err != nil {
+			return nil, err
+		}
+		dbLikers, err := s.dbLikers(global.DB, articleID)
+		if err != nil {
+			return nil, err
+		}
+
+		drift := dto.LikeDriftVO{
+			ArticleID:      articleID,
+			RedisCount:     len(redisLikers),
+			MySQLCount:     len(dbLikers),
+			MissingInRedis: []uint{},
+			MissingInMySQL: []uint{},
+		}
+		for userID := range dbLikers {
+			if !redisLikers[userID] {
+				drift.MissingInRedis = append(drift.MissingInRedis, userID)
+			}
+		}
+		for userID := range redisLikers {
+			if !dbLikers[userID] {
+				drift.MissingInMySQL = append(drift.MissingInMySQL, userID)
+			}
+		}
+		if len(drift.MissingInRedis) == 0 && len(drift.MissingInMySQL) == 0 {
+			continue
+		}
+		drifts = append(drifts, drift)
+
+		// Redis数据在修复过程中被清空时停止，避免用空集合覆盖MySQL
+		marker, err := global.RedisDB.Exists(ctx, global.CacheKeyArticleLikesLoaded).Result()
+		if err != nil {
+			return nil, err
+		}
+		if marker == 0 {
+			return nil, fmt.Errorf("点赞数据正在重建，请稍后重试")
+		}
+
+		// Redis数据丢失后重建的，把MySQL独有的点赞补回Redis；否则以Redis为准回写MySQL
+		if redisLost && len(drift.MissingInRedis) > 0 {
+			members := make([]interface{}, 0, len(drift.MissingInRedis))
+			for _, userID := range drift.MissingInRedis {
+				members = append(members, userID)
+			}
+			if err := global.RedisDB.SAdd(ctx, s.likersKey(articleID), members...).Err(); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.syncArticleLikes(ctx, articleID); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"checked": len(articleIDs),
+		"drifted": len(drifts),
+		"drift":   drifts,
+	}, nil
+}
+
+// redisLikers 读取Redis中文章的点赞用户集合
+func (s *ArticleLikeService) redisLikers(ctx context.Context, articleID uint) (map[uint]bool, error) {
+	members, err := global.RedisDB.SMembers(ctx, s.likersKey(articleID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	likers := make(map[uint]bool, len(members))
+	for _, member := range members {
+		userID, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		likers[uint(userID)] = true
+	}
+	return likers, nil
+}
+
+// dbLikers 读取MySQL中文章的点赞用户集合
+func (s *ArticleLikeService) dbLikers(db *gorm.DB, articleID uint) (map[uint]bool, error) {
+	var userIDs []uint
+	if err := db.Model(&model.ArticleLike{}).Where("article_id = ?", articleID).Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+
+	likers := make(map[uint]bool, len(userIDs))
+	for _, userID := range userIDs {
+		likers[userID] = true
+	}
+	return likers, nil
+}
+
+// checkArticleExists 校验文章存在且已发布，返回需要计入点赞数的旧版本匿名点赞数
+func (s *ArticleLikeService) checkArticleExists(articleID uint) (int64, error) {
+	var article model.Article
+	if err := global.DB.Select("id", "status", "legacy_likes").Where("id = ?", articleID).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, fmt.Errorf("未找到该文章")
+		}
+		return 0, err
+	}
+	if article.Status != global.ArticleStatusPublished {
+		return 0, fmt.Errorf("未找到该文章")
+	}
+	return article.LegacyLikes, nil
+}
 
-	return likes, nil
+// likersKey 文章点赞用户集合的键
+func (s *ArticleLikeService) likersKey(articleID uint) string {
+	return fmt.Sprintf("%s:%d", global.CacheKeyArticleLikes, articleID)
 }
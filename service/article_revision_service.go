@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+
+	"gorm.io/gorm"
+)
+
+// createRevision 在事务中保存文章当前内容的修订快照
+func (s *ArticleService) createRevision(tx *gorm.DB, article model.Article, editorID uint, comment string) error {
+	return tx.Create(&model.ArticleRevision{
+		ArticleID: article.ID,
+		Version:   article.Version,
+		Title:     article.Title,
+		Content:   article.Content,
+		Preview:   article.Preview,
+		EditorID:  editorID,
+		Comment:   comment,
+	}).Error
+}
+
+// GetArticleRevisions 获取文章的修订历史（按版本倒序，不含正文）
+func (s *ArticleService) GetArticleRevisions(articleID uint, operatorID uint, isAdmin bool) ([]dto.ArticleRevisionVO, error) {
+	if err := s.checkRevisionAccess(articleID, operatorID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	var revisions []model.ArticleRevision
+	if err := global.DB.Omit("content").
+		Where("article_id = ?", articleID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	vos := make([]dto.ArticleRevisionVO, 0, len(revisions))
+	for _, r := range revisions {
+		vos = append(vos, s.toRevisionVO(r))
+	}
+	return vos, nil
+}
+
+// GetArticleRevision 获取指定版本的修订快照（含正文）
+func (s *ArticleService) GetArticleRevision(articleID, version uint, operatorID uint, isAdmin bool) (*dto.ArticleRevisionVO, error) {
+	if err := s.checkRevisionAccess(articleID, operatorID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.findRevision(articleID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	vo := s.toRevisionVO(*revision)
+	return &vo, nil
+}
+
+// DiffArticleRevisions 对比两个版本的标题、摘要和正文的行级差异
+func (s *ArticleService) DiffArticleRevisions(articleID, fromVersion, toVersion uint, operatorID uint, isAdmin bool) (map[string]interface{}, error) {
+	if err := s.checkRevisionAccess(articleID, operatorID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevision(articleID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(articleID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// 版本信息中不重复返回正文
+	fromVO := s.toRevisionVO(*from)
+	fromVO.Content = ""
+	toVO := s.toRevisionVO(*to)
+	toVO.Content = ""
+
+	return map[string]interface{}{
+		"article_id": articleID,
+		"from":       fromVO,
+		"to":         toVO,
+		"title":      utils.DiffLines(from.Title, to.Title),
+		"preview":    utils.DiffLines(from.Preview, to.Preview),
+		"content":    utils.DiffLines(from.Content, to.Content),
+	}, nil
+}
+
+// RollbackArticle 将文章回滚到指定版本，回滚本身会生成一个新版本
+func (s *ArticleService) RollbackArticle(articleID, version uint, operatorID uint, isAdmin bool) (*dto.ArticleVO, error) {
+	if err := s.checkRevisionAccess(articleID, operatorID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.findRevision(articleID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var current model.Article
+	if err := global.DB.Select("id", "version").Where("id = ?", articleID).First(&current).Error; err != nil {
+		return nil, err
+	}
+	if current.Version == revision.Version {
+		return nil, fmt.Errorf("该版本已是当前版本")
+	}
+
+	updateData := map[string]interface{}{
+		"title":   revision.Title,
+		"content": revision.Content,
+		"preview": revision.Preview,
+	}
+	return s.applyArticleUpdate(articleID, articleChange{fields: updateData}, current.Version, operatorID, fmt.Sprintf("回滚到版本%d", revision.Version))
+}
+
+// checkRevisionAccess 校验文章存在且当前用户有权查看/回滚修订记录
+func (s *ArticleService) checkRevisionAccess(articleID uint, operatorID uint, isAdmin bool) error {
+	if isAdmin {
+		var count int64
+		if err := global.DB.Model(&model.Article{}).Where("id = ?", articleID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("未找到该文章")
+		}
+		return nil
+	}
+	return s.checkArticleOwner(articleID, operatorID)
+}
+
+// findRevision 查询指定版本的修订快照
+func (s *ArticleService) findRevision(articleID, version uint) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	if err := global.DB.Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("未找到该版本")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// toRevisionVO 将修订模型转换为VO
+func (s *ArticleService) toRevisionVO(r model.ArticleRevision) dto.ArticleRevisionVO {
+	return dto.ArticleRevisionVO{
+		ID:        r.ID,
+		ArticleID: r.ArticleID,
+		Version:   r.Version,
+		Title:     r.Title,
+		Content:   r.Content,
+		Preview:   r.Preview,
+		EditorID:  r.EditorID,
+		Comment:   r.Comment,
+		Created:   r.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"go_test/config"
+	"go_test/global"
+	"go_test/utils"
+	"log"
+	"sync"
+	"time"
+)
+
+// ArticleScheduler 定时发布调度器，周期性发布到期的scheduled文章
+type ArticleScheduler struct {
+	articleService *ArticleService
+	interval       time.Duration
+	lockTTL        time.Duration
+	wg             sync.WaitGroup
+}
+
+func NewArticleScheduler() *ArticleScheduler {
+	interval := 30 * time.Second
+	lockTTL := time.Minute
+	if schedulerConfig := config.GetSchedulerConfig(); schedulerConfig != nil {
+		if schedulerConfig.IntervalSeconds > 0 {
+			interval = time.Duration(schedulerConfig.IntervalSeconds) * time.Second
+		}
+		if schedulerConfig.LockTTLSeconds > 0 {
+			lockTTL = time.Duration(schedulerConfig.LockTTLSeconds) * time.Second
+		}
+	}
+
+	return &ArticleScheduler{
+		articleService: NewArticleService(),
+		interval:       interval,
+		lockTTL:        lockTTL,
+	}
+}
+
+// Start 启动调度协程，ctx取消时退出
+func (s *ArticleScheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.runOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				log.Println("定时发布调度器已停止")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait 等待调度协程退出，用于优雅关闭
+func (s *ArticleScheduler) Wait() {
+	s.wg.Wait()
+}
+
+// runOnce 获取分布式锁后执行一次发布，同一时刻只有一个实例执行
+func (s *ArticleScheduler) runOnce(ctx context.Context) {
+	token, ok, err := utils.AcquireLock(ctx, global.CacheKeyLockArticleScheduler, s.lockTTL)
+	if err != nil {
+		log.Printf("获取定时发布锁失败: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		// 使用独立的context释放锁，避免关闭时ctx已取消导致锁无法释放
+		if err := utils.ReleaseLock(context.Background(), global.CacheKeyLockArticleScheduler, token); err != nil {
+			log.Printf("释放定时发布锁失败: %v", err)
+		}
+	}()
+
+	count, err := s.articleService.PublishDueArticles()
+	if err != nil {
+		log.Printf("定时发布失败: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("定时发布完成，发布%d篇文章", count)
+	}
+}
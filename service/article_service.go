@@ -30,27 +30,63 @@ func NewArticleService() *ArticleService {
 }
 
 // CreateArticle 创建文章业务逻辑
-func (s *ArticleService) CreateArticle(req dto.ArticleRequest) (*dto.ArticleVO, error) {
+func (s *ArticleService) CreateArticle(req dto.ArticleRequest, authorID uint) (*dto.ArticleVO, error) {
 	article := model.Article{
-		Title:   req.Title,
-		Content: req.Content,
-		Preview: req.Preview,
+		Title:      req.Title,
+		Content:    req.Content,
+		Preview:    req.Preview,
+		AuthorID:   &authorID,
+		Status:     global.ArticleStatusDraft, // 新文章默认为草稿，需经审核后发布
+		CategoryID: req.CategoryID,
 	}
 
-	if err := global.DB.Create(&article).Error; err != nil {
+	// 渲染Markdown正文
+	contentHTML, contentTOC, err := s.renderContent(req.Content)
+	if err != nil {
 		return nil, err
 	}
+	article.ContentHTML = contentHTML
+	article.ContentTOC = contentTOC
 
-	// 清除缓存
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if req.CategoryID != nil {
+			if err := checkCategoryExists(tx, *req.CategoryID); err != nil {
+				return err
+			}
+		}
+
+		tags, err := findOrCreateTags(tx, req.Tags)
+		if err != nil {
+			return err
+		}
+		article.Tags = tags
+
+		if err := tx.Create(&article).Error; err != nil {
+			return err
+		}
+
+		// 生成并登记slug
+		if err := s.assignSlug(tx, &article, req.Slug); err != nil {
+			return err
+		}
+		// 保存初始修订快照
+		return s.createRevision(tx, article, authorID, "创建文章")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 回填作者、分类和标签信息
+	if err := s.withRelations(global.DB).First(&article, article.ID).Error; err != nil {
+		return nil, err
+	}
+
+	// 清除缓存并更新搜索索引
 	s.clearAllCache()
+	indexArticle(article)
 
-	return &dto.ArticleVO{
-		ID:      article.ID,
-		Title:   article.Title,
-		Content: article.Content,
-		Preview: article.Preview,
-		Created: article.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+	vo := s.toArticleVO(article)
+	return &vo, nil
 }
 
 // GetAllArticles 获取所有文章业务逻辑
@@ -68,20 +104,14 @@ func (s *ArticleService) GetAllArticles() ([]dto.ArticleVO, error) {
 		if err == redis.Nil {
 			// 缓存仍未命中，从数据库查询
 			var articles []model.Article
-			if err := global.DB.Find(&articles).Error; err != nil {
+			if err := s.withRelations(global.DB).Where("status = ?", global.ArticleStatusPublished).Find(&articles).Error; err != nil {
 				return nil, err
 			}
 
 			// 转换为VO
 			vos := make([]dto.ArticleVO, 0, len(articles))
 			for _, a := range articles {
-				vos = append(vos, dto.ArticleVO{
-					ID:      a.ID,
-					Title:   a.Title,
-					Content: a.Content,
-					Preview: a.Preview,
-					Created: a.CreatedAt.Format("2006-01-02 15:04:05"),
-				})
+				vos = append(vos, s.toArticleVO(a))
 			}
 
 			// 将数据存入缓存
@@ -109,11 +139,16 @@ func (s *ArticleService) GetAllArticles() ([]dto.ArticleVO, error) {
 }
 
 // GetArticlesWithPagination 分页查询文章业务逻辑
-func (s *ArticleService) GetArticlesWithPagination(paginate *utils.Paginate, keyword string) (map[string]interface{}, error) {
-	keyword = strings.TrimSpace(keyword)
+// 支持按关键词、标签和分类筛选
+func (s *ArticleService) GetArticlesWithPagination(paginate *utils.Paginate, filter dto.ArticleFilter) (map[string]interface{}, error) {
+	filter.Keyword = strings.TrimSpace(filter.Keyword)
+	filter.Tag = strings.TrimSpace(filter.Tag)
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.Highlight = resolveHighlight(filter.Highlight)
+	keyword := filter.Keyword
 
 	// 生成缓存键
-	cacheKey := s.generatePaginationCacheKey(paginate.Page, paginate.PageSize, paginate.Order, keyword)
+	cacheKey := s.generatePaginationCacheKey(paginate.Page, paginate.PageSize, paginate.Order, filter)
 
 	// 先尝试读缓存
 	cachedData, err := global.RedisDB.Get(articleCtxRedis, cacheKey).Result()
@@ -127,38 +162,14 @@ func (s *ArticleService) GetArticlesWithPagination(paginate *utils.Paginate, key
 		cachedData, err = global.RedisDB.Get(articleCtxRedis, cacheKey).Result()
 		if err == redis.Nil {
 			// 从数据库查询
-			query := global.DB.Model(&model.Article{})
-
-			// 如果有关键词，添加搜索条件
-			if keyword != "" {
-				searchPattern := "%" + keyword + "%"
-				query = query.Where("title LIKE ? OR content LIKE ?", searchPattern, searchPattern)
-			}
-
-			// 执行分页查询
-			var articles []model.Article
-			if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+			articles, truncated, err := s.queryListPage(paginate, filter)
+			if err != nil {
 				return nil, err
 			}
+			s.attachRelations(articles)
 
 			// 转换为VO
-			vos := make([]dto.ArticleVO, 0, len(articles))
-			for _, a := range articles {
-				title := a.Title
-				content := a.Content
-				if keyword != "" {
-					title = s.highlightKeyword(a.Title, keyword)
-					content = s.highlightKeyword(a.Content, keyword)
-				}
-
-				vos = append(vos, dto.ArticleVO{
-					ID:      a.ID,
-					Title:   title,
-					Content: content,
-					Preview: a.Preview,
-					Created: a.CreatedAt.Format("2006-01-02 15:04:05"),
-				})
-			}
+			vos := s.toListVOs(articles, filter)
 
 			// 构建响应
 			response := map[string]interface{}{
@@ -169,6 +180,14 @@ func (s *ArticleService) GetArticlesWithPagination(paginate *utils.Paginate, key
 			if keyword != "" {
 				response["keyword"] = keyword
 				response["is_search"] = true
+				// 命中数超过搜索上限，总数和靠后的页不完整
+				response["truncated"] = truncated
+			}
+			if filter.Tag != "" {
+				response["tag"] = filter.Tag
+			}
+			if filter.Category != "" {
+				response["category"] = filter.Category
 			}
 
 			// 将数据存入缓存
@@ -186,7 +205,7 @@ func (s *ArticleService) GetArticlesWithPagination(paginate *utils.Paginate, key
 	} else if err != nil {
 		// Redis连接错误，降级到数据库查询
 		fmt.Printf("Redis连接错误: %v\n", err)
-		return s.fallbackDatabaseQuery(paginate, keyword)
+		return s.fallbackDatabaseQuery(paginate, filter)
 	}
 
 	// 缓存命中
@@ -225,14 +244,14 @@ func (s *ArticleService) BatchDeleteArticles(ids []uint, hardDelete bool) error
 	}
 
 	// 执行删除操作
-	var deleteQuery *gorm.DB
+	var err error
 	if hardDelete {
-		deleteQuery = global.DB.Unscoped().Where("id IN ?", ids).Delete(&model.Article{})
+		err = s.hardDeleteArticles(ids)
 	} else {
-		deleteQuery = global.DB.Where("id IN ?", ids).Delete(&model.Article{})
+		err = global.DB.Where("id IN ?", ids).Delete(&model.Article{}).Error
 	}
 
-	if err := deleteQuery.Error; err != nil {
+	if err != nil {
 		deleteType := "硬删除"
 		if !hardDelete {
 			deleteType = "软删除"
@@ -240,34 +259,542 @@ func (s *ArticleService) BatchDeleteArticles(ids []uint, hardDelete bool) error
 		return fmt.Errorf("%s失败: %s", deleteType, err.Error())
 	}
 
-	// 清除缓存
+	// 清除缓存，软删除的文章也从搜索索引中移除
 	s.clearAllCache()
+	if !hardDelete {
+		removeArticlesFromIndex(ids)
+		NewSitemapService().RefreshArticles(ids...)
+	}
 
 	return nil
 }
 
 // GetArticleByID 根据ID获取文章业务逻辑
-func (s *ArticleService) GetArticleByID(id string) (*dto.ArticleVO, error) {
+// 未发布的文章只有作者本人和管理员可见，viewerID为0表示匿名访客
+func (s *ArticleService) GetArticleByID(id string, viewerID uint, isAdmin bool, clientIP string) (*dto.ArticleVO, error) {
 	var article model.Article
-	if err := global.DB.Where("id = ?", id).First(&article).Error; err != nil {
+	if err := s.withRelations(global.DB).Where("id = ?", id).First(&article).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("未找到该文章")
 		}
 		return nil, err
 	}
 
-	return &dto.ArticleVO{
-		ID:      article.ID,
-		Title:   article.Title,
-		Content: article.Content,
-		Preview: article.Preview,
-		Created: article.CreatedAt.Format("2006-01-02 15:04:05"),
+	if article.Status != global.ArticleStatusPublished && !isAdmin && !s.isArticleOwner(article, viewerID) {
+		return nil, fmt.Errorf("未找到该文章")
+	}
+
+	// 只统计已发布文章的浏览
+	if article.Status == global.ArticleStatusPublished {
+		NewArticleViewService().RecordView(article.ID, viewVisitor(viewerID, clientIP))
+	}
+
+	vo := s.toArticleVO(article)
+	return &vo, nil
+}
+
+// GetArticlesByAuthor 分页查询指定作者的文章
+// 作者本人和管理员可以看到所有状态的文章，其他用户只能看到已发布的文章
+func (s *ArticleService) GetArticlesByAuthor(authorID uint, paginate *utils.Paginate, viewerID uint, isAdmin bool) (map[string]interface{}, error) {
+	var author model.User
+	if err := global.DB.Where("id = ?", authorID).First(&author).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("用户不存在")
+		}
+		return nil, err
+	}
+
+	query := global.DB.Model(&model.Article{}).Where("author_id = ?", authorID)
+	if !isAdmin && viewerID != authorID {
+		query = query.Where("status = ?", global.ArticleStatusPublished)
+	}
+
+	var articles []model.Article
+	if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+		return nil, err
+	}
+
+	s.attachRelations(articles)
+
+	vos := make([]dto.ArticleVO, 0, len(articles))
+	for _, a := range articles {
+		vos = append(vos, s.toArticleVO(a))
+	}
+
+	return map[string]interface{}{
+		"articles":   vos,
+		"pagination": paginate.GetPaginationInfo(),
 	}, nil
 }
 
+// UpdateArticle 更新文章业务逻辑（部分更新 + 乐观锁版本校验）
+// 管理员可以编辑所有文章，作者只能编辑自己的文章
+func (s *ArticleService) UpdateArticle(id uint, req dto.ArticleUpdateRequest, expectedVersion uint, operatorID uint, isAdmin bool) (*dto.ArticleVO, error) {
+	// 权限检查
+	if !isAdmin {
+		if err := s.checkArticleOwner(id, operatorID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 构建更新数据
+	updateData := map[string]interface{}{}
+
+	if req.Title != nil {
+		updateData["title"] = *req.Title
+	}
+
+	if req.Content != nil {
+		updateData["content"] = *req.Content
+	}
+
+	if req.Preview != nil {
+		updateData["preview"] = *req.Preview
+	}
+
+	if req.CategoryID != nil {
+		if *req.CategoryID == 0 {
+			updateData["category_id"] = nil
+		} else {
+			if err := checkCategoryExists(global.DB, *req.CategoryID); err != nil {
+				return nil, err
+			}
+			updateData["category_id"] = *req.CategoryID
+		}
+	}
+
+	// 如果没有需要更新的字段
+	if len(updateData) == 0 && req.Tags == nil && req.Slug == nil {
+		return nil, fmt.Errorf("没有需要更新的字段")
+	}
+
+	return s.applyArticleUpdate(id, articleChange{fields: updateData, tags: req.Tags, slug: req.Slug}, expectedVersion, operatorID, "")
+}
+
+// articleChange 一次文章更新涉及的变更
+type articleChange struct {
+	fields map[string]interface{} // 文章表字段
+	tags   *[]string              // 标签名称，nil表示不修改
+	slug   *string                // 新slug，nil表示不修改
+}
+
+// applyArticleUpdate 按版本号更新文章并保存修订快照
+func (s *ArticleService) applyArticleUpdate(id uint, change articleChange, expectedVersion uint, operatorID uint, comment string) (*dto.ArticleVO, error) {
+	// 版本号自增，只有版本号匹配时才会更新成功
+	updateData := change.fields
+	updateData["version"] = gorm.Expr("version + 1")
+
+	// 正文变更时重新渲染HTML和目录
+	if content, ok := updateData["content"].(string); ok {
+		contentHTML, contentTOC, err := s.renderContent(content)
+		if err != nil {
+			return nil, err
+		}
+		updateData["content_html"] = contentHTML
+		updateData["content_toc"] = contentTOC
+	}
+
+	var article model.Article
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Article{}).
+			Where("id = ? AND version = ?", id, expectedVersion).
+			Updates(updateData)
+		if result.Error != nil {
+			return fmt.Errorf("更新文章失败: %v", result.Error)
+		}
+
+		if err := tx.Where("id = ?", id).First(&article).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fmt.Errorf("未找到该文章")
+			}
+			return err
+		}
+
+		// 文章存在但没有行被更新，说明版本号已过期
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("文章已被其他人修改，请刷新后重试")
+		}
+
+		if change.tags != nil {
+			tags, err := findOrCreateTags(tx, *change.tags)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&article).Association("Tags").Replace(tags); err != nil {
+				return fmt.Errorf("更新标签失败: %v", err)
+			}
+		}
+
+		if change.slug != nil {
+			if err := s.assignSlug(tx, &article, *change.slug); err != nil {
+				return err
+			}
+		}
+
+		// 保存修订快照
+		return s.createRevision(tx, article, operatorID, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 清除缓存
+	s.clearAllCache()
+
+	if err := s.withRelations(global.DB).Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, err
+	}
+
+	// 标题或正文变更时更新搜索索引
+	_, titleChanged := updateData["title"]
+	_, contentChanged := updateData["content"]
+	if titleChanged || contentChanged {
+		indexArticle(article)
+	}
+	NewSitemapService().RefreshArticles(article.ID)
+
+	vo := s.toArticleVO(article)
+	return &vo, nil
+}
+
+// toArticleVO 将文章模型转换为VO
+func (s *ArticleService) toArticleVO(a model.Article) dto.ArticleVO {
+	vo := dto.ArticleVO{
+		ID:      a.ID,
+		Slug:    a.Slug,
+		Title:   a.Title,
+		Content: a.Content,
+		Preview: a.Preview,
+		Version: a.Version,
+		Status:  a.Status,
+		Created: a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	vo.ContentHTML, vo.TOC = s.renderedContent(a)
+	if a.PublishedAt != nil {
+		vo.Published = a.PublishedAt.Format("2006-01-02 15:04:05")
+	}
+	if a.Status == global.ArticleStatusScheduled && a.PublishAt != nil {
+		vo.PublishAt = a.PublishAt.Format("2006-01-02 15:04:05")
+	}
+	if a.DeletedAt.Valid {
+		vo.Deleted = a.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	if a.AuthorID != nil {
+		vo.AuthorID = *a.AuthorID
+	}
+	if a.Author != nil {
+		vo.AuthorNickname = a.Author.Nickname
+		vo.AuthorAvatar = a.Author.Avatar
+	}
+	if a.CategoryID != nil {
+		vo.CategoryID = *a.CategoryID
+	}
+	if a.Category != nil {
+		vo.CategoryName = a.Category.Name
+	}
+	vo.Tags = make([]string, 0, len(a.Tags))
+	for _, t := range a.Tags {
+		vo.Tags = append(vo.Tags, t.Name)
+	}
+	vo.CommentCount = a.CommentCount
+	return vo
+}
+
+// renderContent 将Markdown正文渲染为HTML，目录序列化为JSON保存
+func (s *ArticleService) renderContent(content string) (string, string, error) {
+	contentHTML, toc, err := utils.RenderMarkdown(content)
+	if err != nil {
+		return "", "", err
+	}
+	tocJSON, err := json.Marshal(toc)
+	if err != nil {
+		return "", "", fmt.Errorf("序列化目录失败: %v", err)
+	}
+	return contentHTML, string(tocJSON), nil
+}
+
+// renderedContent 读取文章缓存的HTML和目录，历史文章未缓存时实时渲染
+func (s *ArticleService) renderedContent(a model.Article) (string, []dto.TOCItem) {
+	if a.ContentHTML == "" && a.Content != "" {
+		contentHTML, toc, err := utils.RenderMarkdown(a.Content)
+		if err != nil {
+			fmt.Printf("渲染文章%d失败: %v\n", a.ID, err)
+			return "", []dto.TOCItem{}
+		}
+		return contentHTML, toc
+	}
+
+	toc := []dto.TOCItem{}
+	if a.ContentTOC != "" {
+		if err := json.Unmarshal([]byte(a.ContentTOC), &toc); err != nil {
+			fmt.Printf("解析文章%d目录失败: %v\n", a.ID, err)
+		}
+	}
+	return a.ContentHTML, toc
+}
+
+// withRelations 预加载文章的作者、分类和标签（仅用于不需要计数的查询）
+func (s *ArticleService) withRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author").Preload("Category").Preload("Tags")
+}
+
+// attachRelations 批量查询并回填文章的作者、分类和标签，避免分页计数时使用Preload
+func (s *ArticleService) attachRelations(articles []model.Article) {
+	if len(articles) == 0 {
+		return
+	}
+
+	articleIDs := make([]uint, 0, len(articles))
+	authorIDs := make([]uint, 0, len(articles))
+	categoryIDs := make([]uint, 0, len(articles))
+	for _, a := range articles {
+		articleIDs = append(articleIDs, a.ID)
+		if a.AuthorID != nil {
+			authorIDs = append(authorIDs, *a.AuthorID)
+		}
+		if a.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *a.CategoryID)
+		}
+	}
+
+	authorMap := make(map[uint]*model.User)
+	if len(authorIDs) > 0 {
+		var authors []model.User
+		if err := global.DB.Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+			fmt.Printf("查询文章作者失败: %v\n", err)
+		}
+		for i := range authors {
+			authorMap[authors[i].ID] = &authors[i]
+		}
+	}
+
+	categoryMap := make(map[uint]*model.Category)
+	if len(categoryIDs) > 0 {
+		var categories []model.Category
+		if err := global.DB.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+			fmt.Printf("查询文章分类失败: %v\n", err)
+		}
+		for i := range categories {
+			categoryMap[categories[i].ID] = &categories[i]
+		}
+	}
+
+	var tagRows []struct {
+		ArticleID uint
+		model.Tag
+	}
+	if err := global.DB.Table("article_tags").
+		Select("article_tags.article_id, tags.*").
+		Joins("JOIN tags ON tags.id = article_tags.tag_id").
+		Where("article_tags.article_id IN ?", articleIDs).
+		Order("tags.name ASC").
+		Scan(&tagRows).Error; err != nil {
+		fmt.Printf("查询文章标签失败: %v\n", err)
+	}
+	tagMap := make(map[uint][]model.Tag)
+	for _, row := range tagRows {
+		tagMap[row.ArticleID] = append(tagMap[row.ArticleID], row.Tag)
+	}
+
+	for i := range articles {
+		if articles[i].AuthorID != nil {
+			articles[i].Author = authorMap[*articles[i].AuthorID]
+		}
+		if articles[i].CategoryID != nil {
+			articles[i].Category = categoryMap[*articles[i].CategoryID]
+		}
+		articles[i].Tags = tagMap[articles[i].ID]
+	}
+}
+
+// hardDeleteArticles 彻底删除文章及其标签关联、修订、状态流转、slug、评论和点赞记录
+func (s *ArticleService) hardDeleteArticles(ids []uint) error {
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.ArticleRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.ArticleStatusLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("article_id IN ?", ids).Delete(&model.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN ?", ids).Delete(&model.ArticleLike{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Article{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	removeArticlesFromIndex(ids)
+	NewSitemapService().RefreshArticles(ids...)
+
+	// 清除Redis中的点赞用户集合和点赞榜
+	likeKeys := make([]string, 0, len(ids))
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		likeKeys = append(likeKeys, fmt.Sprintf("%s:%d", global.CacheKeyArticleLikes, id))
+		members = append(members, id)
+	}
+	if err := global.RedisDB.Del(articleCtxRedis, likeKeys...).Err(); err != nil {
+		fmt.Printf("清除点赞缓存失败: %v\n", err)
+	}
+	if err := global.RedisDB.ZRem(articleCtxRedis, global.CacheKeyLeaderboardLike, members...).Err(); err != nil {
+		fmt.Printf("清除点赞榜失败: %v\n", err)
+	}
+	return nil
+}
+
+// checkArticleOwner 校验文章是否属于指定用户
+func (s *ArticleService) checkArticleOwner(articleID, userID uint) error {
+	var article model.Article
+	if err := global.DB.Select("id", "author_id").Where("id = ?", articleID).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("未找到该文章")
+		}
+		return err
+	}
+
+	if !s.isArticleOwner(article, userID) {
+		return fmt.Errorf("权限不足，只能编辑自己的文章")
+	}
+	return nil
+}
+
+// isArticleOwner 判断用户是否为文章作者
+func (s *ArticleService) isArticleOwner(article model.Article, userID uint) bool {
+	return article.AuthorID != nil && *article.AuthorID == userID
+}
+
+// buildListQuery 构建已发布文章列表的查询条件（标签、分类）
+// 关键词搜索由搜索引擎处理，见queryListPage
+func (s *ArticleService) buildListQuery(filter dto.ArticleFilter) *gorm.DB {
+	query := global.DB.Model(&model.Article{}).Where("articles.status = ?", global.ArticleStatusPublished)
+
+	if filter.Tag != "" {
+		query = query.Where("articles.id IN (?)", global.DB.Table("article_tags").
+			Select("article_tags.article_id").
+			Joins("JOIN tags ON tags.id = article_tags.tag_id").
+			Where("tags.name = ?", filter.Tag))
+	}
+
+	if filter.Category != "" {
+		query = query.Where("articles.category_id IN (?)", global.DB.Model(&model.Category{}).
+			Select("id").
+			Where("name = ?", filter.Category))
+	}
+
+	return query
+}
+
+// queryListPage 查询一页已发布文章
+// 有关键词时由搜索引擎按相关度排序：支持ScopedSearcher的引擎直接在查询中应用标签、分类条件并分页；
+// 其他引擎先取最多searchMaxResults条命中再过滤分页，命中数达到上限时truncated为true，此时总数和靠后的页不完整
+func (s *ArticleService) queryListPage(paginate *utils.Paginate, filter dto.ArticleFilter) (articles []model.Article, truncated bool, err error) {
+	query := s.buildListQuery(filter)
+	if filter.Keyword == "" {
+		if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+			return nil, false, err
+		}
+		return articles, false, nil
+	}
+
+	if searchEngine == nil {
+		return nil, false, fmt.Errorf("搜索引擎未初始化")
+	}
+
+	var pageIDs []uint
+	if scoped, ok := searchEngine.(ScopedSearcher); ok {
+		hits, total, err := scoped.SearchScoped(filter.Keyword, query, (paginate.Page-1)*paginate.PageSize, paginate.PageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		paginate.Total = total
+		for _, hit := range hits {
+			pageIDs = append(pageIDs, hit.ID)
+		}
+	} else {
+		pageIDs, truncated, err = s.filterSearchHits(paginate, filter, query)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if len(pageIDs) == 0 {
+		return []model.Article{}, truncated, nil
+	}
+
+	if err := global.DB.Where("id IN ?", pageIDs).Find(&articles).Error; err != nil {
+		return nil, false, err
+	}
+	articleMap := make(map[uint]model.Article, len(articles))
+	for _, a := range articles {
+		articleMap[a.ID] = a
+	}
+	ordered := make([]model.Article, 0, len(pageIDs))
+	for _, id := range pageIDs {
+		if a, ok := articleMap[id]; ok {
+			ordered = append(ordered, a)
+		}
+	}
+	return ordered, truncated, nil
+}
+
+// filterSearchHits 取最多searchMaxResults条命中，按query的条件过滤后保持相关度顺序分页，返回当前页的文章ID
+func (s *ArticleService) filterSearchHits(paginate *utils.Paginate, filter dto.ArticleFilter, query *gorm.DB) ([]uint, bool, error) {
+	hits, err := searchEngine.Search(filter.Keyword, searchMaxResults)
+	if err != nil {
+		return nil, false, err
+	}
+	truncated := len(hits) >= searchMaxResults
+	if len(hits) == 0 {
+		paginate.Total = 0
+		return nil, false, nil
+	}
+
+	hitIDs := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		hitIDs = append(hitIDs, hit.ID)
+	}
+	var matchedIDs []uint
+	if err := query.Where("articles.id IN ?", hitIDs).Pluck("articles.id", &matchedIDs).Error; err != nil {
+		return nil, false, err
+	}
+	matched := make(map[uint]bool, len(matchedIDs))
+	for _, id := range matchedIDs {
+		matched[id] = true
+	}
+
+	// 保持搜索引擎返回的相关度顺序
+	rankedIDs := make([]uint, 0, len(matchedIDs))
+	for _, id := range hitIDs {
+		if matched[id] {
+			rankedIDs = append(rankedIDs, id)
+		}
+	}
+	paginate.Total = int64(len(rankedIDs))
+
+	start := (paginate.Page - 1) * paginate.PageSize
+	if start >= len(rankedIDs) {
+		return nil, truncated, nil
+	}
+	end := start + paginate.PageSize
+	if end > len(rankedIDs) {
+		end = len(rankedIDs)
+	}
+	return rankedIDs[start:end], truncated, nil
+}
+
 // generatePaginationCacheKey 生成分页查询的缓存键
-func (s *ArticleService) generatePaginationCacheKey(page, pageSize int, order, keyword string) string {
-	keyStr := fmt.Sprintf("page:%d_size:%d_order:%s_keyword:%s", page, pageSize, order, keyword)
+func (s *ArticleService) generatePaginationCacheKey(page, pageSize int, order string, filter dto.ArticleFilter) string {
+	keyStr := fmt.Sprintf("page:%d_size:%d_order:%s_keyword:%s_tag:%s_category:%s_highlight:%s",
+		page, pageSize, order, filter.Keyword, filter.Tag, filter.Category, filter.Highlight)
 	hash := md5.Sum([]byte(keyStr))
 	return fmt.Sprintf("%s:%x", global.CacheKeyArticlesPagination, hash)
 }
@@ -280,6 +807,9 @@ func (s *ArticleService) clearAllCache() {
 	}
 	// 清除分页缓存
 	s.clearPaginationCache()
+	// 清除订阅源和站点地图缓存
+	NewFeedService().clearFeedCache()
+	NewSitemapService().clearRenderCache()
 }
 
 // clearPaginationCache 清除分页相关的所有缓存
@@ -297,58 +827,34 @@ func (s *ArticleService) clearPaginationCache() {
 	}
 }
 
-// highlightKeyword 高亮关键词
-func (s *ArticleService) highlightKeyword(text, keyword string) string {
-	if keyword == "" {
-		return text
-	}
-
-	lowerText := strings.ToLower(text)
-	lowerKeyword := strings.ToLower(keyword)
-
-	if !strings.Contains(lowerText, lowerKeyword) {
-		return text
+// toListVOs 转换列表页文章，搜索时返回高亮标题和正文摘要
+func (s *ArticleService) toListVOs(articles []model.Article, filter dto.ArticleFilter) []dto.ArticleVO {
+	var terms []string
+	if filter.Keyword != "" {
+		terms = utils.ParseSearchQuery(filter.Keyword)
 	}
 
-	index := strings.Index(lowerText, lowerKeyword)
-	if index == -1 {
-		return text
+	vos := make([]dto.ArticleVO, 0, len(articles))
+	for _, a := range articles {
+		if len(terms) > 0 {
+			vos = append(vos, s.toSearchResultVO(a, terms, filter.Highlight))
+			continue
+		}
+		vos = append(vos, s.toArticleVO(a))
 	}
-
-	originalKeyword := text[index : index+len(keyword)]
-	return strings.Replace(text, originalKeyword, "**"+originalKeyword+"**", -1)
+	return vos
 }
 
 // fallbackDatabaseQuery 数据库降级查询
-func (s *ArticleService) fallbackDatabaseQuery(paginate *utils.Paginate, keyword string) (map[string]interface{}, error) {
-	query := global.DB.Model(&model.Article{})
-	if keyword != "" {
-		searchPattern := "%" + keyword + "%"
-		query = query.Where("title LIKE ? OR content LIKE ?", searchPattern, searchPattern)
-	}
-
-	var articles []model.Article
-	if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+func (s *ArticleService) fallbackDatabaseQuery(paginate *utils.Paginate, filter dto.ArticleFilter) (map[string]interface{}, error) {
+	keyword := filter.Keyword
+	articles, truncated, err := s.queryListPage(paginate, filter)
+	if err != nil {
 		return nil, err
 	}
+	s.attachRelations(articles)
 
-	vos := make([]dto.ArticleVO, 0, len(articles))
-	for _, a := range articles {
-		title := a.Title
-		content := a.Content
-		if keyword != "" {
-			title = s.highlightKeyword(a.Title, keyword)
-			content = s.highlightKeyword(a.Content, keyword)
-		}
-
-		vos = append(vos, dto.ArticleVO{
-			ID:      a.ID,
-			Title:   title,
-			Content: content,
-			Preview: a.Preview,
-			Created: a.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
-	}
+	vos := s.toListVOs(articles, filter)
 
 	response := map[string]interface{}{
 		"articles":   vos,
@@ -358,6 +864,13 @@ func (s *ArticleService) fallbackDatabaseQuery(paginate *utils.Paginate, keyword
 	if keyword != "" {
 		response["keyword"] = keyword
 		response["is_search"] = true
+		response["truncated"] = truncated
+	}
+	if filter.Tag != "" {
+		response["tag"] = filter.Tag
+	}
+	if filter.Category != "" {
+		response["category"] = filter.Category
 	}
 
 	return response, nil
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// maxSlugAttempts 生成slug时尝试的最大冲突后缀数量，超过后使用短哈希后缀
+const maxSlugAttempts = 20
+
+// GetArticleBySlug 根据slug获取文章
+// 如果slug是文章的历史slug，返回文章当前的slug用于301重定向；viewerID为0表示匿名访客
+func (s *ArticleService) GetArticleBySlug(slug string, viewerID uint, isAdmin bool, clientIP string) (*dto.ArticleVO, string, error) {
+	var record model.ArticleSlug
+	if err := global.DB.Where("slug = ?", slug).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", fmt.Errorf("未找到该文章")
+		}
+		return nil, "", err
+	}
+
+	var article model.Article
+	if err := s.withRelations(global.DB).Where("id = ?", record.ArticleID).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", fmt.Errorf("未找到该文章")
+		}
+		return nil, "", err
+	}
+
+	if article.Status != global.ArticleStatusPublished && !isAdmin && !s.isArticleOwner(article, viewerID) {
+		return nil, "", fmt.Errorf("未找到该文章")
+	}
+
+	// 历史slug，需要重定向到当前slug
+	if article.Slug != slug {
+		return nil, article.Slug, nil
+	}
+
+	if article.Status == global.ArticleStatusPublished {
+		NewArticleViewService().RecordView(article.ID, viewVisitor(viewerID, clientIP))
+	}
+
+	vo := s.toArticleVO(article)
+	return &vo, "", nil
+}
+
+// BackfillArticleSlugs 为没有slug的历史文章生成slug，启动时调用
+func (s *ArticleService) BackfillArticleSlugs() {
+	var articles []model.Article
+	if err := global.DB.Unscoped().Select("id", "title", "slug").Where("slug = '' OR slug IS NULL").Find(&articles).Error; err != nil {
+		log.Printf("查询待生成slug的文章失败: %v", err)
+		return
+	}
+
+	for i := range articles {
+		err := global.DB.Transaction(func(tx *gorm.DB) error {
+			return s.assignSlug(tx, &articles[i], "")
+		})
+		if err != nil {
+			log.Printf("为文章%d生成slug失败: %v", articles[i].ID, err)
+		}
+	}
+	if len(articles) > 0 {
+		log.Printf("已为%d篇历史文章生成slug", len(articles))
+	}
+}
+
+// assignSlug 在事务中为文章设置slug并登记到slug表，旧slug保留用于重定向
+// desired为空时根据标题生成；标题无法转换时使用短哈希；冲突时追加数字后缀
+func (s *ArticleService) assignSlug(tx *gorm.DB, article *model.Article, desired string) error {
+	base := utils.Slugify(desired)
+	if desired != "" && base == "" {
+		return fmt.Errorf("slug格式无效，只能包含字母、数字和汉字")
+	}
+	if base == "" {
+		base = utils.Slugify(article.Title)
+	}
+	if base == "" {
+		base = utils.ShortHash(fmt.Sprintf("%d:%s", article.ID, article.Title))
+	}
+
+	slug, err := s.findAvailableSlug(tx, article.ID, base)
+	if err != nil {
+		return err
+	}
+	if slug == article.Slug {
+		return nil
+	}
+
+	// 新slug登记到slug表（该文章曾用过的slug已存在则复用）
+	var existing model.ArticleSlug
+	err = tx.Where("slug = ?", slug).First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		if err := tx.Create(&model.ArticleSlug{ArticleID: article.ID, Slug: slug}).Error; err != nil {
+			return fmt.Errorf("保存slug失败: %v", err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Model(&model.Article{}).Where("id = ?", article.ID).Update("slug", slug).Error; err != nil {
+		return fmt.Errorf("保存slug失败: %v", err)
+	}
+	article.Slug = slug
+	return nil
+}
+
+// findAvailableSlug 查找未被其他文章占用的slug
+func (s *ArticleService) findAvailableSlug(tx *gorm.DB, articleID uint, base string) (string, error) {
+	candidate := base
+	for i := 2; i <= maxSlugAttempts+1; i++ {
+		var owner model.ArticleSlug
+		err := tx.Where("slug = ?", candidate).First(&owner).Error
+		if err == gorm.ErrRecordNotFound || (err == nil && owner.ArticleID == articleID) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	// 冲突过多时追加短哈希
+	return fmt.Sprintf("%s-%s", base, utils.ShortHash(fmt.Sprintf("%d:%s", articleID, base))), nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"go_test/config"
+	"log"
+	"sync"
+	"time"
+)
+
+// ArticleStatsWorker 浏览统计任务：消费浏览事件写入Redis，并定期将每日统计持久化到MySQL
+type ArticleStatsWorker struct {
+	viewService   *ArticleViewService
+	flushInterval time.Duration
+	wg            sync.WaitGroup
+}
+
+func NewArticleStatsWorker() *ArticleStatsWorker {
+	flushInterval := time.Minute
+	if viewConfig := config.GetViewConfig(); viewConfig != nil && viewConfig.FlushIntervalSeconds > 0 {
+		flushInterval = time.Duration(viewConfig.FlushIntervalSeconds) * time.Second
+	}
+
+	return &ArticleStatsWorker{
+		viewService:   NewArticleViewService(),
+		flushInterval: flushInterval,
+	}
+}
+
+// Start 启动统计协程，ctx取消时写完队列中的事件并持久化后退出
+func (w *ArticleStatsWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		writeTicker := time.NewTicker(time.Second)
+		defer writeTicker.Stop()
+		flushTicker := time.NewTicker(w.flushInterval)
+		defer flushTicker.Stop()
+
+		batch := make([]viewEvent, 0, viewBatchSize)
+		writeBatch := func(ctx context.Context) {
+			if len(batch) == 0 {
+				return
+			}
+			if err := w.viewService.writeViewEvents(ctx, batch); err != nil {
+				log.Printf("写入浏览统计失败: %v", err)
+			}
+			batch = batch[:0]
+		}
+
+		for {
+			select {
+			case e := <-viewEvents:
+				batch = append(batch, e)
+				if len(batch) >= viewBatchSize {
+					writeBatch(ctx)
+				}
+			case <-writeTicker.C:
+				writeBatch(ctx)
+			case <-flushTicker.C:
+				writeBatch(ctx)
+				w.persist(ctx)
+			case <-ctx.Done():
+				// 使用独立的context写完剩余事件，避免退出时丢失统计
+				bgCtx := context.Background()
+				for drained := false; !drained; {
+					select {
+					case e := <-viewEvents:
+						batch = append(batch, e)
+					default:
+						drained = true
+					}
+				}
+				writeBatch(bgCtx)
+				w.persist(bgCtx)
+				log.Println("浏览统计任务已停止")
+				return
+			}
+		}
+	}()
+}
+
+// Wait 等待统计协程退出，用于优雅关闭
+func (w *ArticleStatsWorker) Wait() {
+	w.wg.Wait()
+}
+
+// persist 持久化今天和昨天的统计，昨天的数据用于补齐跨零点前最后一段时间的浏览
+func (w *ArticleStatsWorker) persist(ctx context.Context) {
+	now := time.Now()
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		if _, err := w.viewService.PersistDailyStats(ctx, day); err != nil {
+			log.Printf("持久化%s浏览统计失败: %v", day.Format("2006-01-02"), err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// articleTransitions 文章状态机：当前状态 -> 允许流转到的状态
+var articleTransitions = map[string][]string{
+	global.ArticleStatusDraft:     {global.ArticleStatusInReview},
+	global.ArticleStatusInReview:  {global.ArticleStatusDraft, global.ArticleStatusScheduled, global.ArticleStatusPublished},
+	global.ArticleStatusScheduled: {global.ArticleStatusInReview, global.ArticleStatusPublished},
+	global.ArticleStatusPublished: {global.ArticleStatusArchived},
+	global.ArticleStatusArchived:  {global.ArticleStatusDraft},
+}
+
+// authorTransitions 作者可以自行执行的流转（提交审核、撤回审核），其余流转需要管理员操作
+var authorTransitions = map[string]string{
+	global.ArticleStatusDraft:    global.ArticleStatusInReview,
+	global.ArticleStatusInReview: global.ArticleStatusDraft,
+}
+
+// TransitionArticle 文章状态流转业务逻辑
+func (s *ArticleService) TransitionArticle(id uint, req dto.ArticleTransitionRequest, operatorID uint, isAdmin bool) (*dto.ArticleVO, error) {
+	var article model.Article
+	if err := global.DB.Where("id = ?", id).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("未找到该文章")
+		}
+		return nil, err
+	}
+
+	// 状态机校验
+	if !canTransition(article.Status, req.Status) {
+		return nil, fmt.Errorf("不允许从%s流转到%s", article.Status, req.Status)
+	}
+
+	// 权限检查：作者只能对自己的文章执行提交审核和撤回
+	if !isAdmin {
+		if !s.isArticleOwner(article, operatorID) {
+			return nil, fmt.Errorf("权限不足，只能编辑自己的文章")
+		}
+		if authorTransitions[article.Status] != req.Status {
+			return nil, fmt.Errorf("权限不足，该操作需要管理员审核")
+		}
+	}
+
+	// 定时发布必须指定未来的发布时间
+	if req.Status == global.ArticleStatusScheduled && (req.PublishAt == nil || !req.PublishAt.After(time.Now())) {
+		return nil, fmt.Errorf("定时发布时间必须晚于当前时间")
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		updateData := map[string]interface{}{"status": req.Status}
+		if req.Status == global.ArticleStatusScheduled {
+			updateData["publish_at"] = *req.PublishAt
+		}
+		if req.Status == global.ArticleStatusPublished && article.PublishedAt == nil {
+			updateData["published_at"] = time.Now()
+		}
+
+		// 以当前状态为条件更新，防止并发流转
+		result := tx.Model(&model.Article{}).
+			Where("id = ? AND status = ?", id, article.Status).
+			Updates(updateData)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("文章状态已变更，请刷新后重试")
+		}
+
+		return tx.Create(&model.ArticleStatusLog{
+			ArticleID:  id,
+			FromStatus: article.Status,
+			ToStatus:   req.Status,
+			OperatorID: operatorID,
+			Comment:    req.Comment,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 清除缓存，发布或下线时更新站点地图
+	s.clearAllCache()
+	NewSitemapService().RefreshArticles(id)
+
+	if err := s.withRelations(global.DB).Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, err
+	}
+
+	vo := s.toArticleVO(article)
+	return &vo, nil
+}
+
+// PublishDueArticles 发布已到定时发布时间的文章，返回发布数量
+// 以status=scheduled为条件逐篇更新，即使多个实例同时执行也只会发布一次
+func (s *ArticleService) PublishDueArticles() (int, error) {
+	var articles []model.Article
+	if err := global.DB.Select("id", "publish_at", "published_at").
+		Where("status = ? AND publish_at <= ?", global.ArticleStatusScheduled, time.Now()).
+		Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	publishedIDs := make([]uint, 0, len(articles))
+	for _, a := range articles {
+		updated := false
+		err := global.DB.Transaction(func(tx *gorm.DB) error {
+			updateData := map[string]interface{}{"status": global.ArticleStatusPublished}
+			if a.PublishedAt == nil {
+				updateData["published_at"] = *a.PublishAt
+			}
+
+			result := tx.Model(&model.Article{}).
+				Where("id = ? AND status = ?", a.ID, global.ArticleStatusScheduled).
+				Updates(updateData)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// 已被其他实例发布或已取消定时
+				return nil
+			}
+			updated = true
+
+			return tx.Create(&model.ArticleStatusLog{
+				ArticleID:  a.ID,
+				FromStatus: global.ArticleStatusScheduled,
+				ToStatus:   global.ArticleStatusPublished,
+				OperatorID: 0, // 系统操作
+				Comment:    "定时发布",
+			}).Error
+		})
+		if err != nil {
+			return published, err
+		}
+		if updated {
+			published++
+			publishedIDs = append(publishedIDs, a.ID)
+		}
+	}
+
+	if published > 0 {
+		s.clearAllCache()
+		NewSitemapService().RefreshArticles(publishedIDs...)
+	}
+	return published, nil
+}
+
+// GetArticleStatusLogs 获取文章状态流转记录
+func (s *ArticleService) GetArticleStatusLogs(id uint, operatorID uint, isAdmin bool) ([]dto.ArticleStatusLogVO, error) {
+	if !isAdmin {
+		if err := s.checkArticleOwner(id, operatorID); err != nil {
+			return nil, err
+		}
+	}
+
+	var logs []model.ArticleStatusLog
+	if err := global.DB.Where("article_id = ?", id).Order("id ASC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	vos := make([]dto.ArticleStatusLogVO, 0, len(logs))
+	for _, l := range logs {
+		vos = append(vos, dto.ArticleStatusLogVO{
+			ID:         l.ID,
+			ArticleID:  l.ArticleID,
+			FromStatus: l.FromStatus,
+			ToStatus:   l.ToStatus,
+			OperatorID: l.OperatorID,
+			Comment:    l.Comment,
+			Created:    l.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return vos, nil
+}
+
+// GetArticlesByStatus 按状态分页查询文章（管理员审核队列等场景）
+func (s *ArticleService) GetArticlesByStatus(status string, paginate *utils.Paginate) (map[string]interface{}, error) {
+	if _, ok := articleTransitions[status]; !ok {
+		return nil, fmt.Errorf("文章状态参数无效")
+	}
+
+	query := global.DB.Model(&model.Article{}).Where("status = ?", status)
+
+	var articles []model.Article
+	if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+		return nil, err
+	}
+	s.attachRelations(articles)
+
+	vos := make([]dto.ArticleVO, 0, len(articles))
+	for _, a := range articles {
+		vos = append(vos, s.toArticleVO(a))
+	}
+
+	return map[string]interface{}{
+		"articles":   vos,
+		"pagination": paginate.GetPaginationInfo(),
+	}, nil
+}
+
+// canTransition 判断状态机是否允许该流转
+func canTransition(from, to string) bool {
+	for _, next := range articleTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
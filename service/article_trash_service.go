@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+	"time"
+)
+
+// GetTrashedArticles 分页查询回收站中的文章（已软删除）
+func (s *ArticleService) GetTrashedArticles(paginate *utils.Paginate) (map[string]interface{}, error) {
+	query := global.DB.Unscoped().Model(&model.Article{}).Where("deleted_at IS NOT NULL")
+
+	// 回收站默认按删除时间倒序
+	if paginate.Order == global.DefaultOrder {
+		paginate.Order = "deleted_at DESC"
+	}
+
+	var articles []model.Article
+	if err := utils.PaginateWithCondition(query, paginate, &articles); err != nil {
+		return nil, err
+	}
+	s.attachRelations(articles)
+
+	vos := make([]dto.ArticleVO, 0, len(articles))
+	for _, a := range articles {
+		vos = append(vos, s.toArticleVO(a))
+	}
+
+	return map[string]interface{}{
+		"articles":   vos,
+		"pagination": paginate.GetPaginationInfo(),
+	}, nil
+}
+
+// RestoreArticles 从回收站恢复文章
+func (s *ArticleService) RestoreArticles(ids []uint) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("恢复ID列表不能为空")
+	}
+
+	// 验证ID是否都在回收站中
+	if err := s.checkTrashedIDs(ids, "恢复"); err != nil {
+		return err
+	}
+
+	if err := global.DB.Unscoped().Model(&model.Article{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Update("deleted_at", nil).Error; err != nil {
+		return fmt.Errorf("恢复失败: %s", err.Error())
+	}
+
+	// 清除缓存，恢复的文章重新加入搜索索引
+	s.clearAllCache()
+	var articles []model.Article
+	if err := global.DB.Select("id", "title", "content").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		log.Printf("加载恢复文章失败: %v", err)
+	}
+	for _, a := range articles {
+		indexArticle(a)
+	}
+	NewSitemapService().RefreshArticles(ids...)
+
+	return nil
+}
+
+// PurgeArticles 从回收站中彻底删除文章
+func (s *ArticleService) PurgeArticles(ids []uint) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("删除ID列表不能为空")
+	}
+
+	// 只允许清理回收站中的文章，正常文章需先软删除
+	if err := s.checkTrashedIDs(ids, "彻底删除"); err != nil {
+		return err
+	}
+
+	if err := s.hardDeleteArticles(ids); err != nil {
+		return fmt.Errorf("彻底删除失败: %s", err.Error())
+	}
+
+	return nil
+}
+
+// PurgeExpiredArticles 彻底删除超过保留期限的回收站文章，返回删除数量
+func (s *ArticleService) PurgeExpiredArticles(retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+
+	var ids []uint
+	if err := global.DB.Unscoped().Model(&model.Article{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if err := s.hardDeleteArticles(ids); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// StartTrashPurgeJob 启动回收站自动清理任务，ctx取消时退出
+func (s *ArticleService) StartTrashPurgeJob(ctx context.Context) {
+	trashConfig := config.GetTrashConfig()
+	if trashConfig == nil || trashConfig.RetentionDays <= 0 {
+		log.Println("回收站自动清理未启用")
+		return
+	}
+
+	interval := time.Duration(trashConfig.PurgeIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	retention := time.Duration(trashConfig.RetentionDays) * 24 * time.Hour
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if count, err := s.PurgeExpiredArticles(retention); err != nil {
+				log.Printf("回收站自动清理失败: %v", err)
+			} else if count > 0 {
+				log.Printf("回收站自动清理完成，彻底删除%d篇文章", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkTrashedIDs 校验ID是否都在回收站中
+func (s *ArticleService) checkTrashedIDs(ids []uint, action string) error {
+	var count int64
+	if err := global.DB.Unscoped().Model(&model.Article{}).
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if int(count) != len(ids) {
+		return fmt.Errorf("部分文章不在回收站中，请求%s %d个，实际可%s %d个", action, len(ids), action, count)
+	}
+	return nil
+}
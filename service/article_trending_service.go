@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 热度分值权重
+const (
+	trendingLikeScore = 5.0 // 每次点赞
+	trendingViewScore = 1.0 // 每个独立访客每天首次浏览
+
+	trendingBucketTTL    = 31 * 24 * time.Hour // 分桶保留时间，需覆盖最长的时间窗口
+	trendingWindowTTL    = time.Minute         // 合并结果缓存时间
+	trendingMaxLimit     = 50
+	trendingDefaultLimit = 10
+)
+
+// trendingWindows 支持的热度窗口及包含的小时数
+var trendingWindows = map[string]int{
+	"24h": 24,
+	"7d":  7 * 24,
+	"30d": 30 * 24,
+}
+
+// bumpTrendingScore 增加文章在当前小时分桶中的热度
+func bumpTrendingScore(ctx context.Context, articleID uint, score float64) error {
+	bucketKey := trendingBucketKey(time.Now())
+	pipe := global.RedisDB.Pipeline()
+	pipe.ZIncrBy(ctx, bucketKey, score, strconv.FormatUint(uint64(articleID), 10))
+	pipe.Expire(ctx, bucketKey, trendingBucketTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// trendingBucketKey 指定时间所在小时的分桶键
+func trendingBucketKey(t time.Time) string {
+	return fmt.Sprintf("%s:%s", global.CacheKeyTrendingBucket, t.Format("2006010215"))
+}
+
+// GetTrendingArticles 获取排行榜文章（按排名顺序，只返回摘要）
+// window为24h/7d/30d时按时间衰减的热度排序，为all时按累计点赞数排序
+func (s *ArticleService) GetTrendingArticles(window string, limit int) ([]dto.ArticleVO, error) {
+	if limit <= 0 {
+		limit = trendingDefaultLimit
+	}
+	if limit > trendingMaxLimit {
+		limit = trendingMaxLimit
+	}
+
+	var rankKey string
+	if window == "all" {
+		rankKey = global.CacheKeyLeaderboardLike
+	} else {
+		hours, ok := trendingWindows[window]
+		if !ok {
+			return nil, fmt.Errorf("时间窗口参数无效，只能是24h、7d、30d或all")
+		}
+		key, err := s.mergeTrendingWindow(window, hours)
+		if err != nil {
+			return nil, err
+		}
+		rankKey = key
+	}
+
+	// 多取一些候选，过滤掉未发布或已删除的文章后仍能凑够数量
+	members, err := global.RedisDB.ZRevRange(articleCtxRedis, rankKey, 0, int64(limit*2-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		return []dto.ArticleVO{}, nil
+	}
+
+	var articles []model.Article
+	if err := global.DB.Where("id IN ? AND status = ?", ids, global.ArticleStatusPublished).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	s.attachRelations(articles)
+
+	articleMap := make(map[uint]model.Article, len(articles))
+	for _, a := range articles {
+		articleMap[a.ID] = a
+	}
+
+	vos := make([]dto.ArticleVO, 0, limit)
+	for _, id := range ids {
+		a, ok := articleMap[id]
+		if !ok {
+			continue
+		}
+		vo := s.toArticleVO(a)
+		// 排行榜只返回摘要，不返回正文
+		vo.Content = ""
+		vo.ContentHTML = ""
+		vo.TOC = []dto.TOCItem{}
+		vos = append(vos, vo)
+		if len(vos) >= limit {
+			break
+		}
+	}
+	return vos, nil
+}
+
+// mergeTrendingWindow 合并时间窗口内的小时分桶，越早的分桶权重越低（半衰期为窗口的一半）
+// 合并结果缓存一分钟，避免每次请求都合并上百个分桶
+func (s *ArticleService) mergeTrendingWindow(window string, hours int) (string, error) {
+	windowKey := fmt.Sprintf("%s:%s", global.CacheKeyTrendingWindow, window)
+	exists, err := global.RedisDB.Exists(articleCtxRedis, windowKey).Result()
+	if err != nil {
+		return "", err
+	}
+	if exists > 0 {
+		return windowKey, nil
+	}
+
+	halfLife := float64(hours) / 2
+	now := time.Now()
+	keys := make([]string, 0, hours)
+	weights := make([]float64, 0, hours)
+	for age := 0; age < hours; age++ {
+		keys = append(keys, trendingBucketKey(now.Add(-time.Duration(age)*time.Hour)))
+		weights = append(weights, math.Pow(0.5, float64(age)/halfLife))
+	}
+
+	pipe := global.RedisDB.TxPipeline()
+	pipe.ZUnionStore(articleCtxRedis, windowKey, &redis.ZStore{Keys: keys, Weights: weights})
+	pipe.Expire(articleCtxRedis, windowKey, trendingWindowTTL)
+	if _, err := pipe.Exec(articleCtxRedis); err != nil {
+		return "", err
+	}
+	return windowKey, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	viewEventBuffer  = 4096               // 浏览事件缓冲区大小，写满时丢弃新事件
+	viewBatchSize    = 200                // 每批写入Redis的事件数量
+	viewKeyTTL       = 3 * 24 * time.Hour // 每日计数在Redis中的保留时间，需大于持久化间隔
+	viewMaxRangeDays = 366                // 统计查询的最大天数
+)
+
+// viewEvent 一次文章浏览
+type viewEvent struct {
+	articleID uint
+	visitor   string
+	at        time.Time
+}
+
+// viewEvents 浏览事件队列，由ArticleStatsWorker异步写入Redis，避免拖慢文章读取
+var viewEvents = make(chan viewEvent, viewEventBuffer)
+
+type ArticleViewService struct{}
+
+func NewArticleViewService() *ArticleViewService {
+	return &ArticleViewService{}
+}
+
+// RecordView 记录一次文章浏览，visitor用于独立访客去重
+// 只把事件放入队列，不等待Redis；队列已满时丢弃，浏览统计不影响文章读取
+func (s *ArticleViewService) RecordView(articleID uint, visitor string) {
+	select {
+	case viewEvents <- viewEvent{articleID: articleID, visitor: visitor, at: time.Now()}:
+	default:
+	}
+}
+
+// viewVisitor 独立访客标识，登录用户按用户ID，匿名访客按IP
+func viewVisitor(viewerID uint, clientIP string) string {
+	if viewerID != 0 {
+		return fmt.Sprintf("u:%d", viewerID)
+	}
+	return "ip:" + clientIP
+}
+
+// writeViewEvents 批量写入浏览计数、独立访客和热度
+// 热度只计入当天新出现的访客，同一访客反复刷新不会推高排名
+func (s *ArticleViewService) writeViewEvents(ctx context.Context, events []viewEvent) error {
+	pipe := global.RedisDB.Pipeline()
+	newVisitors := make([]*redis.IntCmd, len(events))
+	for i, e := range events {
+		day := e.at.Format("20060102")
+		member := strconv.FormatUint(uint64(e.articleID), 10)
+
+		viewsKey := fmt.Sprintf("%s:%s", global.CacheKeyArticleViews, day)
+		pipe.HIncrBy(ctx, viewsKey, member, 1)
+		pipe.Expire(ctx, viewsKey, viewKeyTTL)
+
+		uvKey := s.uvKey(day, e.articleID)
+		newVisitors[i] = pipe.PFAdd(ctx, uvKey, e.visitor)
+		pipe.Expire(ctx, uvKey, viewKeyTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	// PFAdd返回1表示HyperLogLog发生变化，即该访客当天首次浏览这篇文章
+	trendingPipe := global.RedisDB.Pipeline()
+	scored := 0
+	for i, e := range events {
+		if newVisitors[i].Val() != 1 {
+			continue
+		}
+		bucketKey := trendingBucketKey(e.at)
+		trendingPipe.ZIncrBy(ctx, bucketKey, trendingViewScore, strconv.FormatUint(uint64(e.articleID), 10))
+		trendingPipe.Expire(ctx, bucketKey, trendingBucketTTL)
+		scored++
+	}
+	if scored == 0 {
+		return nil
+	}
+	_, err := trendingPipe.Exec(ctx)
+	return err
+}
+
+// PersistDailyStats 将指定日期的浏览计数持久化到MySQL，返回写入的文章数量
+// 写入的是Redis中当天的累计值，重复执行是幂等的；取较大值防止Redis数据丢失后覆盖已有统计
+func (s *ArticleViewService) PersistDailyStats(ctx context.Context, day time.Time) (int, error) {
+	dayKey := day.Format("20060102")
+	counts, err := global.RedisDB.HGetAll(ctx, fmt.Sprintf("%s:%s", global.CacheKeyArticleViews, dayKey)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	stats := make([]model.ArticleStat, 0, len(counts))
+	for member, value := range counts {
+		articleID, err := strconv.ParseUint(member, 10, 32)
+		if err != nil {
+			continue
+		}
+		views, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		uniqueViewers, err := global.RedisDB.PFCount(ctx, s.uvKey(dayKey, uint(articleID))).Result()
+		if err != nil {
+			return 0, err
+		}
+		stats = append(stats, model.ArticleStat{
+			ArticleID:     uint(articleID),
+			Date:          date,
+			Views:         views,
+			UniqueViewers: uniqueViewers,
+		})
+	}
+	if len(stats) == 0 {
+		return 0, nil
+	}
+
+	err = global.DB.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "article_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"views":          gorm.Expr("GREATEST(views, VALUES(views))"),
+			"unique_viewers": gorm.Expr("GREATEST(unique_viewers, VALUES(unique_viewers))"),
+			"updated_at":     time.Now(),
+		}),
+	}).CreateInBatches(&stats, 500).Error
+	if err != nil {
+		return 0, fmt.Errorf("保存浏览统计失败: %v", err)
+	}
+	return len(stats), nil
+}
+
+// GetArticleStats 查询文章在日期范围内每天的浏览次数和独立访客数，没有数据的日期补0
+func (s *ArticleViewService) GetArticleStats(articleID uint, from, to time.Time) (map[string]interface{}, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("结束日期不能早于开始日期")
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > viewMaxRangeDays {
+		return nil, fmt.Errorf("查询范围不能超过%d天", viewMaxRangeDays)
+	}
+
+	var count int64
+	if err := global.DB.Unscoped().Model(&model.Article{}).Where("id = ?", articleID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("未找到该文章")
+	}
+
+	var stats []model.ArticleStat
+	if err := global.DB.Where("article_id = ? AND date BETWEEN ? AND ?", articleID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	statMap := make(map[string]model.ArticleStat, len(stats))
+	for _, stat := range stats {
+		statMap[stat.Date.Format("2006-01-02")] = stat
+	}
+
+	var totalViews int64
+	vos := make([]dto.ArticleStatVO, 0, days)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		stat := statMap[date]
+		vos = append(vos, dto.ArticleStatVO{
+			Date:          date,
+			Views:         stat.Views,
+			UniqueViewers: stat.UniqueViewers,
+		})
+		totalViews += stat.Views
+	}
+
+	return map[string]interface{}{
+		"article_id":  articleID,
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+		"data":        vos,
+		"total_views": totalViews,
+	}, nil
+}
+
+// uvKey 文章某天的独立访客HyperLogLog键
+func (s *ArticleViewService) uvKey(day string, articleID uint) string {
+	return fmt.Sprintf("%s:%s:%d", global.CacheKeyArticleUV, day, articleID)
+}
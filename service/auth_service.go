@@ -6,8 +6,18 @@ import (
 	"go_test/global"
 	"go_test/model"
 	"go_test/utils"
+	"log"
+	"sync"
+
+	"gorm.io/gorm"
 )
 
+// dummyPasswordHash 用户不存在时用于比对的密码哈希，使两种失败的耗时一致
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := utils.HashPassword("dummy-password-for-timing")
+	return hash
+})
+
 type AuthService struct{}
 
 func NewAuthService() *AuthService {
@@ -15,15 +25,15 @@ func NewAuthService() *AuthService {
 }
 
 // Register 用户注册业务逻辑
-func (s *AuthService) Register(user model.User) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(user model.User, client dto.ClientInfo) (*dto.AuthResponse, error) {
 	// 如果没有指定角色，默认为普通用户
 	if user.Role == "" {
 		user.Role = global.RoleUser
 	}
 
 	// 验证角色是否有效
-	if user.Role != global.RoleAdmin && user.Role != global.RoleUser {
-		return nil, fmt.Errorf("角色参数无效，只能是admin或user")
+	if user.Role != global.RoleAdmin && user.Role != global.RoleAuthor && user.Role != global.RoleUser {
+		return nil, fmt.Errorf("角色参数无效，只能是admin、author或user")
 	}
 
 	// 对密码进行加密
@@ -38,43 +48,97 @@ func (s *AuthService) Register(user model.User) (*dto.AuthResponse, error) {
 		return nil, fmt.Errorf("注册失败，用户名可能已存在")
 	}
 
-	// 生成JWT令牌（包含用户ID）
-	token, err := utils.GenerateJWT(user.Username, user.Role, user.ID)
+	// 填写了邮箱时发送验证邮件，失败不影响注册
+	if user.Email != "" {
+		if err := NewAccountService().sendVerification(user); err != nil {
+			log.Printf("发送验证邮件失败: %v", err)
+		}
+	}
+
+	// 必须启用两步验证的账户（如管理员）注册后先绑定，不直接签发令牌
+	challenge, err := NewTwoFactorService().loginChallenge(user)
 	if err != nil {
-		return nil, fmt.Errorf("生成令牌失败")
+		return nil, err
+	}
+	if challenge != nil {
+		challenge.Message = "注册成功，" + challenge.Message
+		return challenge, nil
+	}
+
+	// 创建登录会话并签发访问令牌和刷新令牌
+	tokens, err := NewTokenService().StartSession(user, client)
+	if err != nil {
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
-		Username: user.Username,
-		Role:     user.Role,
-		Token:    token,
-		Message:  "注册成功",
+		Username:     user.Username,
+		Role:         user.Role,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Message:      "注册成功",
 	}, nil
 }
 
 // Login 用户登录业务逻辑
-func (s *AuthService) Login(req dto.LoginRequest) (*dto.AuthResponse, error) {
-	// 查询用户
+func (s *AuthService) Login(req dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	// 先原子地预占一次尝试再校验密码，并发请求无法绕过失败次数限制
+	guard := NewLoginGuardService()
+	attempt, err := guard.Acquire(req.Username, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	// 查询用户，用户不存在时同样比对一次密码，避免通过响应时间判断用户名是否存在
 	var user model.User
 	if err := global.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
-		return nil, fmt.Errorf("用户不存在")
+		if err != gorm.ErrRecordNotFound {
+			attempt.Release()
+			return nil, err
+		}
+		utils.CheckPassword(req.Password, dummyPasswordHash())
+		attempt.Fail()
+		return nil, fmt.Errorf("用户名或密码错误")
 	}
 
-	// 校验密码
+	// 校验密码，用户名不存在和密码错误返回相同的错误
 	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, fmt.Errorf("密码错误")
+		attempt.Fail()
+		return nil, fmt.Errorf("用户名或密码错误")
+	}
+	attempt.Release()
+
+	// 密码正确后才提示账户已禁用，不会暴露账户状态
+	if user.Status != global.UserStatusActive {
+		return nil, fmt.Errorf("用户账户已被禁用")
+	}
+
+	// 启用了两步验证或账户必须启用时，只返回挑战令牌，由 VerifyChallenge 完成登录
+	challenge, err := NewTwoFactorService().loginChallenge(user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
 	}
+	// 完成登录后才清除失败记录，需要两步验证的在 VerifyChallenge 成功后清除
+	guard.Reset(req.Username)
 
-	// 生成JWT令牌（包含用户ID）
-	token, err := utils.GenerateJWT(user.Username, user.Role, user.ID)
+	// 创建登录会话并签发访问令牌和刷新令牌，顺便清理该用户过期的刷新令牌
+	tokenService := NewTokenService()
+	tokenService.PurgeExpiredTokens(user.ID)
+	tokens, err := tokenService.StartSession(user, client)
 	if err != nil {
-		return nil, fmt.Errorf("生成令牌失败")
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
-		Username: user.Username,
-		Role:     user.Role,
-		Token:    token,
-		Message:  "登录成功",
+		Username:     user.Username,
+		Role:         user.Role,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Message:      "登录成功",
 	}, nil
 }
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type CategoryService struct{}
+
+func NewCategoryService() *CategoryService {
+	return &CategoryService{}
+}
+
+// CreateCategory 创建分类业务逻辑
+func (s *CategoryService) CreateCategory(req dto.CategoryRequest) (*dto.CategoryVO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("分类名称不能为空")
+	}
+
+	category := model.Category{Name: name, Description: req.Description}
+	if err := global.DB.Create(&category).Error; err != nil {
+		return nil, fmt.Errorf("创建失败，分类可能已存在")
+	}
+
+	return &dto.CategoryVO{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		Created:     category.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// UpdateCategory 更新分类业务逻辑
+func (s *CategoryService) UpdateCategory(id uint, req dto.CategoryRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("分类名称不能为空")
+	}
+
+	var existing model.Category
+	if err := global.DB.Where("name = ? AND id != ?", name, id).First(&existing).Error; err == nil {
+		return fmt.Errorf("分类名称已存在")
+	}
+
+	result := global.DB.Model(&model.Category{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":        name,
+		"description": req.Description,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("更新分类失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("分类不存在")
+	}
+
+	// 文章VO中包含分类名称，需要清除文章缓存
+	NewArticleService().clearAllCache()
+	return nil
+}
+
+// DeleteCategory 删除分类，该分类下的文章变为未分类
+func (s *CategoryService) DeleteCategory(id uint) error {
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&model.Article{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("分类不存在")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	NewArticleService().clearAllCache()
+	return nil
+}
+
+// GetCategoriesWithCount 获取所有分类及每个分类下已发布文章数量
+func (s *CategoryService) GetCategoriesWithCount() ([]dto.CategoryVO, error) {
+	var rows []struct {
+		model.Category
+		ArticleCount int64
+	}
+	err := global.DB.Model(&model.Category{}).
+		Select("categories.*, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN articles ON articles.category_id = categories.id AND articles.status = ? AND articles.deleted_at IS NULL", global.ArticleStatusPublished).
+		Group("categories.id").
+		Order("categories.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	vos := make([]dto.CategoryVO, 0, len(rows))
+	for _, r := range rows {
+		vos = append(vos, dto.CategoryVO{
+			ID:           r.ID,
+			Name:         r.Name,
+			Description:  r.Description,
+			ArticleCount: r.ArticleCount,
+			Created:      r.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return vos, nil
+}
+
+// checkCategoryExists 校验分类是否存在
+func checkCategoryExists(tx *gorm.DB, id uint) error {
+	var count int64
+	if err := tx.Model(&model.Category{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("分类不存在")
+	}
+	return nil
+}
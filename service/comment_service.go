@@ -0,0 +1,408 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type CommentService struct{}
+
+func NewCommentService() *CommentService {
+	return &CommentService{}
+}
+
+// CreateComment 发表评论或回复，只能评论已发布的文章，只能回复自己可见的评论
+func (s *CommentService) CreateComment(articleID uint, req dto.CommentRequest, authorID uint, isAdmin bool) (*dto.CommentVO, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("评论内容不能为空")
+	}
+
+	var article model.Article
+	if err := global.DB.Select("id", "status").Where("id = ?", articleID).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("未找到该文章")
+		}
+		return nil, err
+	}
+	if article.Status != global.ArticleStatusPublished {
+		return nil, fmt.Errorf("未找到该文章")
+	}
+
+	comment := model.Comment{
+		ArticleID: articleID,
+		AuthorID:  authorID,
+		Body:      body,
+		Status:    global.CommentStatusApproved,
+	}
+	if commentConfig := config.GetCommentConfig(); commentConfig != nil && commentConfig.RequireApproval {
+		comment.Status = global.CommentStatusPending
+	}
+
+	// 回复评论时记录父评论和所属顶层评论
+	if req.ParentID != nil {
+		var parent model.Comment
+		if err := global.DB.Where("id = ? AND article_id = ?", *req.ParentID, articleID).First(&parent).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, fmt.Errorf("回复的评论不存在")
+			}
+			return nil, err
+		}
+		// 待审核或已隐藏的评论对回复者不可见时，按不存在处理
+		if !s.isVisible(parent, authorID, isAdmin) {
+			return nil, fmt.Errorf("回复的评论不存在")
+		}
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+	}
+
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return fmt.Errorf("发表评论失败: %v", err)
+		}
+		if comment.Status == global.CommentStatusApproved {
+			return s.adjustCommentCount(tx, articleID, 1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 文章VO中包含评论数，需要清除文章缓存
+	NewArticleService().clearAllCache()
+
+	if err := global.DB.Preload("Author").First(&comment, comment.ID).Error; err != nil {
+		return nil, err
+	}
+	vo := s.toCommentVO(comment)
+	return &vo, nil
+}
+
+// UpdateComment 编辑自己的评论
+func (s *CommentService) UpdateComment(id uint, req dto.CommentUpdateRequest, operatorID uint) (*dto.CommentVO, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("评论内容不能为空")
+	}
+
+	comment, err := s.findComment(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != operatorID {
+		return nil, fmt.Errorf("权限不足，只能编辑自己的评论")
+	}
+
+	if err := global.DB.Model(comment).Update("body", body).Error; err != nil {
+		return nil, fmt.Errorf("编辑评论失败: %v", err)
+	}
+
+	if err := global.DB.Preload("Author").First(comment, id).Error; err != nil {
+		return nil, err
+	}
+	vo := s.toCommentVO(*comment)
+	return &vo, nil
+}
+
+// DeleteComment 删除评论（软删除），作者可删除自己的评论，管理员可删除任意评论
+// 被删除的评论如果还有回复，会在回复树中保留占位
+func (s *CommentService) DeleteComment(id uint, operatorID uint, isAdmin bool) error {
+	comment, err := s.findComment(id)
+	if err != nil {
+		return err
+	}
+	if !isAdmin && comment.AuthorID != operatorID {
+		return fmt.Errorf("权限不足，只能删除自己的评论")
+	}
+
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(comment).Error; err != nil {
+			return fmt.Errorf("删除评论失败: %v", err)
+		}
+		if comment.Status == global.CommentStatusApproved {
+			return s.adjustCommentCount(tx, comment.ArticleID, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	NewArticleService().clearAllCache()
+	return nil
+}
+
+// ModerateComment 管理员审核评论：approved 通过，hidden 隐藏
+func (s *CommentService) ModerateComment(id uint, status string) (*dto.CommentVO, error) {
+	comment, err := s.findComment(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.Status != status {
+		err = global.DB.Transaction(func(tx *gorm.DB) error {
+			// 以当前状态为条件更新，防止并发审核重复计数
+			result := tx.Model(&model.Comment{}).
+				Where("id = ? AND status = ?", id, comment.Status).
+				Update("status", status)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("评论状态已变更，请刷新后重试")
+			}
+
+			if status == global.CommentStatusApproved {
+				return s.adjustCommentCount(tx, comment.ArticleID, 1)
+			}
+			if comment.Status == global.CommentStatusApproved {
+				return s.adjustCommentCount(tx, comment.ArticleID, -1)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		NewArticleService().clearAllCache()
+	}
+
+	if err := global.DB.Preload("Author").First(comment, id).Error; err != nil {
+		return nil, err
+	}
+	vo := s.toCommentVO(*comment)
+	return &vo, nil
+}
+
+// GetArticleComments 分页获取文章评论树，分页按顶层评论计算，每条顶层评论附带完整回复树
+// 普通用户只能看到已通过的评论和自己的待审核评论，管理员可以看到全部未删除的评论
+func (s *CommentService) GetArticleComments(articleID uint, paginate *utils.Paginate, viewerID uint, isAdmin bool) (map[string]interface{}, error) {
+	var article model.Article
+	if err := global.DB.Select("id", "status", "author_id").Where("id = ?", articleID).First(&article).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("未找到该文章")
+		}
+		return nil, err
+	}
+	if article.Status != global.ArticleStatusPublished && !isAdmin && !NewArticleService().isArticleOwner(article, viewerID) {
+		return nil, fmt.Errorf("未找到该文章")
+	}
+
+	// 顶层评论本身可见，或者其下还有可见的回复
+	visibleSQL, visibleArgs := s.visibleCondition("comments", viewerID, isAdmin)
+	replySQL, replyArgs := s.visibleCondition("r", viewerID, isAdmin)
+	query := global.DB.Unscoped().Model(&model.Comment{}).
+		Where("comments.article_id = ? AND comments.parent_id IS NULL", articleID).
+		Where("("+visibleSQL+") OR EXISTS (SELECT 1 FROM comments r WHERE r.root_id = comments.id AND "+replySQL+")",
+			append(visibleArgs, replyArgs...)...)
+
+	var roots []model.Comment
+	if err := utils.PaginateWithCondition(query, paginate, &roots); err != nil {
+		return nil, err
+	}
+
+	comments := roots
+	if len(roots) > 0 {
+		rootIDs := make([]uint, 0, len(roots))
+		for _, c := range roots {
+			rootIDs = append(rootIDs, c.ID)
+		}
+
+		var replies []model.Comment
+		if err := global.DB.Unscoped().
+			Where("root_id IN ?", rootIDs).
+			Order("created_at ASC, id ASC").
+			Find(&replies).Error; err != nil {
+			return nil, err
+		}
+		comments = append(comments, replies...)
+	}
+	s.attachAuthors(comments)
+
+	return map[string]interface{}{
+		"comments":   s.buildCommentTree(comments, len(roots), viewerID, isAdmin),
+		"pagination": paginate.GetPaginationInfo(),
+	}, nil
+}
+
+// GetCommentsByStatus 管理员按状态分页查询评论（审核队列）
+func (s *CommentService) GetCommentsByStatus(status string, paginate *utils.Paginate) (map[string]interface{}, error) {
+	if status != global.CommentStatusPending && status != global.CommentStatusApproved && status != global.CommentStatusHidden {
+		return nil, fmt.Errorf("评论状态参数无效")
+	}
+
+	var comments []model.Comment
+	query := global.DB.Model(&model.Comment{}).Where("status = ?", status)
+	if err := utils.PaginateWithCondition(query, paginate, &comments); err != nil {
+		return nil, err
+	}
+	s.attachAuthors(comments)
+
+	vos := make([]dto.CommentVO, 0, len(comments))
+	for _, c := range comments {
+		vos = append(vos, s.toCommentVO(c))
+	}
+
+	return map[string]interface{}{
+		"comments":   vos,
+		"pagination": paginate.GetPaginationInfo(),
+		"status":     status,
+	}, nil
+}
+
+// commentNode 构建回复树时使用的节点
+type commentNode struct {
+	comment  model.Comment
+	children []*commentNode
+}
+
+// buildCommentTree 将顶层评论（前rootCount条）和回复组装为树
+// 对当前用户不可见的评论，如果还有可见的回复则保留为占位，否则从树中移除
+func (s *CommentService) buildCommentTree(comments []model.Comment, rootCount int, viewerID uint, isAdmin bool) []dto.CommentVO {
+	nodes := make(map[uint]*commentNode, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = &commentNode{comment: c}
+	}
+
+	for _, c := range comments[rootCount:] {
+		parentID := *c.RootID
+		if c.ParentID != nil {
+			if _, ok := nodes[*c.ParentID]; ok {
+				parentID = *c.ParentID
+			}
+		}
+		if parent, ok := nodes[parentID]; ok {
+			parent.children = append(parent.children, nodes[c.ID])
+		}
+	}
+
+	vos := make([]dto.CommentVO, 0, rootCount)
+	for _, c := range comments[:rootCount] {
+		if vo, ok := s.toCommentTreeVO(nodes[c.ID], viewerID, isAdmin); ok {
+			vos = append(vos, vo)
+		}
+	}
+	return vos
+}
+
+// toCommentTreeVO 递归转换评论节点，返回false表示该节点及其回复都不可见
+func (s *CommentService) toCommentTreeVO(node *commentNode, viewerID uint, isAdmin bool) (dto.CommentVO, bool) {
+	replies := make([]dto.CommentVO, 0, len(node.children))
+	for _, child := range node.children {
+		if vo, ok := s.toCommentTreeVO(child, viewerID, isAdmin); ok {
+			replies = append(replies, vo)
+		}
+	}
+
+	c := node.comment
+	visible := s.isVisible(c, viewerID, isAdmin)
+	if !visible && len(replies) == 0 {
+		return dto.CommentVO{}, false
+	}
+
+	// 不可见的评论只保留占位所需的字段，不返回作者、状态和时间
+	if !visible {
+		return dto.CommentVO{
+			ID:       c.ID,
+			ParentID: c.ParentID,
+			Deleted:  c.DeletedAt.Valid,
+			Replies:  replies,
+		}, true
+	}
+	vo := s.toCommentVO(c)
+	vo.Replies = replies
+	return vo, true
+}
+
+// isVisible 判断评论对当前用户是否可见，与visibleCondition保持一致
+func (s *CommentService) isVisible(c model.Comment, viewerID uint, isAdmin bool) bool {
+	if c.DeletedAt.Valid {
+		return false
+	}
+	return isAdmin || c.Status == global.CommentStatusApproved || c.AuthorID == viewerID
+}
+
+// visibleCondition 生成评论可见性的SQL条件
+func (s *CommentService) visibleCondition(alias string, viewerID uint, isAdmin bool) (string, []interface{}) {
+	if isAdmin {
+		return alias + ".deleted_at IS NULL", nil
+	}
+	return alias + ".deleted_at IS NULL AND (" + alias + ".status = ? OR " + alias + ".author_id = ?)",
+		[]interface{}{global.CommentStatusApproved, viewerID}
+}
+
+// adjustCommentCount 在事务中调整文章的评论数，不更新文章的updated_at
+func (s *CommentService) adjustCommentCount(tx *gorm.DB, articleID uint, delta int) error {
+	return tx.Unscoped().Model(&model.Article{}).
+		Where("id = ?", articleID).
+		UpdateColumn("comment_count", gorm.Expr("comment_count + ?", delta)).Error
+}
+
+// attachAuthors 批量查询并回填评论作者
+func (s *CommentService) attachAuthors(comments []model.Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	authorIDs := make([]uint, 0, len(comments))
+	for _, c := range comments {
+		authorIDs = append(authorIDs, c.AuthorID)
+	}
+
+	var authors []model.User
+	if err := global.DB.Where("id IN ?", authorIDs).Find(&authors).Error; err != nil {
+		fmt.Printf("查询评论作者失败: %v\n", err)
+	}
+	authorMap := make(map[uint]*model.User, len(authors))
+	for i := range authors {
+		authorMap[authors[i].ID] = &authors[i]
+	}
+
+	for i := range comments {
+		comments[i].Author = authorMap[comments[i].AuthorID]
+	}
+}
+
+// findComment 查询未删除的评论
+func (s *CommentService) findComment(id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := global.DB.Where("id = ?", id).First(&comment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("未找到该评论")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// toCommentVO 将评论模型转换为VO
+func (s *CommentService) toCommentVO(c model.Comment) dto.CommentVO {
+	vo := dto.CommentVO{
+		ID:        c.ID,
+		ArticleID: c.ArticleID,
+		ParentID:  c.ParentID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		Status:    c.Status,
+		Created:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+		Updated:   c.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Replies:   []dto.CommentVO{},
+	}
+	if c.Author != nil {
+		vo.AuthorNickname = c.Author.Nickname
+		vo.AuthorAvatar = c.Author.Avatar
+	}
+	return vo
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// testComment 构造评论，parent为0表示顶层评论
+func testComment(id, root, parent, author uint, status string, deleted bool) model.Comment {
+	c := model.Comment{ID: id, ArticleID: 1, AuthorID: author, Body: fmt.Sprintf("body%d", id), Status: status}
+	if root != 0 {
+		c.RootID = &root
+	}
+	if parent != 0 {
+		c.ParentID = &parent
+	}
+	if deleted {
+		c.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	}
+	return c
+}
+
+// formatCommentTree 把评论树写成紧凑形式：ID(回复,...)，占位评论前加"?"，已删除的占位加"~"
+func formatCommentTree(vos []dto.CommentVO) string {
+	parts := make([]string, 0, len(vos))
+	for _, vo := range vos {
+		s := fmt.Sprint(vo.ID)
+		if vo.Deleted {
+			s = "~" + s
+		} else if vo.Body == "" {
+			s = "?" + s
+		}
+		if len(vo.Replies) > 0 {
+			s += "(" + formatCommentTree(vo.Replies) + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestBuildCommentTree(t *testing.T) {
+	const (
+		approved = global.CommentStatusApproved
+		pending  = global.CommentStatusPending
+	)
+	tests := []struct {
+		name      string
+		comments  []model.Comment // 顶层评论在前，回复在后
+		rootCount int
+		viewerID  uint
+		isAdmin   bool
+		want      string
+	}{
+		{
+			name: "按父评论嵌套",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, false),
+				testComment(2, 0, 0, 10, approved, false),
+				testComment(3, 1, 1, 11, approved, false),
+				testComment(4, 1, 3, 12, approved, false),
+				testComment(5, 2, 2, 11, approved, false),
+			},
+			rootCount: 2,
+			want:      "1(3(4)),2(5)",
+		},
+		{
+			name: "父评论未加载时挂在顶层评论下",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, false),
+				testComment(3, 1, 2, 11, approved, false),
+			},
+			rootCount: 1,
+			want:      "1(3)",
+		},
+		{
+			name: "他人待审核的评论不可见",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, false),
+				testComment(2, 1, 1, 11, pending, false),
+			},
+			rootCount: 1,
+			viewerID:  12,
+			want:      "1",
+		},
+		{
+			name: "作者能看到自己待审核的评论",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, false),
+				testComment(2, 1, 1, 11, pending, false),
+			},
+			rootCount: 1,
+			viewerID:  11,
+			want:      "1(2)",
+		},
+		{
+			name: "管理员能看到所有未删除的评论",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, pending, false),
+				testComment(2, 1, 1, 11, global.CommentStatusHidden, false),
+				testComment(3, 1, 1, 11, approved, true),
+			},
+			rootCount: 1,
+			isAdmin:   true,
+			want:      "1(2)",
+		},
+		{
+			name: "已删除但有可见回复时保留占位",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, true),
+				testComment(2, 1, 1, 11, approved, false),
+			},
+			rootCount: 1,
+			want:      "~1(2)",
+		},
+		{
+			name: "不可见但有可见回复时保留占位",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, pending, false),
+				testComment(2, 1, 1, 11, approved, false),
+			},
+			rootCount: 1,
+			viewerID:  12,
+			want:      "?1(2)",
+		},
+		{
+			name: "整楼都不可见时不返回",
+			comments: []model.Comment{
+				testComment(1, 0, 0, 10, approved, true),
+				testComment(3, 0, 0, 10, approved, false),
+				testComment(2, 1, 1, 11, pending, false),
+			},
+			rootCount: 2,
+			viewerID:  12,
+			want:      "3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatCommentTree(NewCommentService().buildCommentTree(tt.comments, tt.rootCount, tt.viewerID, tt.isAdmin))
+			if got != tt.want {
+				t.Errorf("tree = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// 占位评论不暴露作者、状态和时间
+func TestCommentPlaceholderFields(t *testing.T) {
+	comments := []model.Comment{
+		testComment(1, 0, 0, 10, global.CommentStatusPending, false),
+		testComment(2, 1, 1, 11, global.CommentStatusApproved, false),
+	}
+	comments[0].Author = &model.User{Nickname: "alice", Avatar: "a.png"}
+
+	vos := NewCommentService().buildCommentTree(comments, 1, 12, false)
+	if len(vos) != 1 {
+		t.Fatalf("len = %d, want 1", len(vos))
+	}
+	placeholder := vos[0]
+	placeholder.Replies = nil
+	want := dto.CommentVO{ID: 1}
+	if !reflect.DeepEqual(placeholder, want) {
+		t.Errorf("placeholder = %+v, want %+v", placeholder, want)
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"net/url"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// 订阅源格式
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+const defaultFeedSize = 20
+
+var feedCtxRedis = context.Background()
+
+// feedFormats 各格式的文件名和Content-Type
+var feedFormats = map[string]struct {
+	file        string
+	contentType string
+}{
+	FeedFormatRSS:  {file: "feed.xml", contentType: "application/rss+xml; charset=utf-8"},
+	FeedFormatAtom: {file: "atom.xml", contentType: "application/atom+xml; charset=utf-8"},
+	FeedFormatJSON: {file: "feed.json", contentType: "application/feed+json; charset=utf-8"},
+}
+
+// FeedScope 订阅源范围，Tag和AuthorID都为空时为全站
+type FeedScope struct {
+	Tag      string
+	AuthorID uint
+}
+
+// FeedResult 渲染好的订阅源，整体缓存在Redis中
+type FeedResult struct {
+	Body         string    `json:"body"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+type FeedService struct{}
+
+func NewFeedService() *FeedService {
+	return &FeedService{}
+}
+
+// GetFeed 获取最新已发布文章的订阅源，优先读缓存
+func (s *FeedService) GetFeed(format string, scope FeedScope) (*FeedResult, error) {
+	if _, ok := feedFormats[format]; !ok {
+		return nil, fmt.Errorf("不支持的订阅源格式: %s", format)
+	}
+
+	cacheKey := s.cacheKey(format, scope)
+	cachedData, err := global.RedisDB.Get(feedCtxRedis, cacheKey).Result()
+	if err == nil {
+		var result FeedResult
+		if err := json.Unmarshal([]byte(cachedData), &result); err == nil {
+			return &result, nil
+		}
+	} else if err != redis.Nil {
+		fmt.Printf("读取订阅源缓存失败: %v\n", err)
+	}
+
+	result, err := s.buildFeed(format, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("序列化订阅源失败: %v", err)
+	}
+	if err := global.RedisDB.Set(feedCtxRedis, cacheKey, resultJSON, time.Duration(global.CacheExpireArticles)*time.Second).Err(); err != nil {
+		fmt.Printf("写入订阅源缓存失败: %v\n", err)
+	}
+	return result, nil
+}
+
+// buildFeed 查询文章并渲染订阅源
+func (s *FeedService) buildFeed(format string, scope FeedScope) (*FeedResult, error) {
+	site := currentSiteConfig()
+
+	feed := utils.Feed{
+		Title:       site.Title,
+		Description: site.Description,
+		Link:        site.BaseURL + "/",
+		FeedURL:     site.BaseURL + s.feedPath(format, scope),
+		Language:    site.Language,
+		Author:      site.Title,
+	}
+
+	query := global.DB.Model(&model.Article{}).Where("articles.status = ?", global.ArticleStatusPublished)
+	if scope.Tag != "" {
+		var tag model.Tag
+		if err := global.DB.Where("name = ?", scope.Tag).First(&tag).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, fmt.Errorf("标签不存在")
+			}
+			return nil, err
+		}
+		query = query.Where("articles.id IN (?)", global.DB.Table("article_tags").Select("article_id").Where("tag_id = ?", tag.ID))
+		feed.Title = fmt.Sprintf("%s - 标签：%s", site.Title, tag.Name)
+	}
+	if scope.AuthorID != 0 {
+		var author model.User
+		if err := global.DB.Select("id", "username", "nickname").Where("id = ?", scope.AuthorID).First(&author).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, fmt.Errorf("用户不存在")
+			}
+			return nil, err
+		}
+		query = query.Where("articles.author_id = ?", author.ID)
+		feed.Title = fmt.Sprintf("%s - 作者：%s", site.Title, authorName(&author))
+	}
+
+	var articles []model.Article
+	if err := query.Order("COALESCE(articles.published_at, articles.created_at) DESC").
+		Limit(site.FeedSize).
+		Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	articleService := NewArticleService()
+	articleService.attachRelations(articles)
+
+	feed.Items = make([]utils.FeedItem, 0, len(articles))
+	for _, a := range articles {
+		published := a.CreatedAt
+		if a.PublishedAt != nil {
+			published = *a.PublishedAt
+		}
+		updated := a.UpdatedAt
+		if updated.Before(published) {
+			updated = published
+		}
+		if updated.After(feed.Updated) {
+			feed.Updated = updated
+		}
+
+		contentHTML, _ := articleService.renderedContent(a)
+		link := articleURL(site, a.Slug, a.ID)
+		item := utils.FeedItem{
+			ID:          link,
+			Title:       a.Title,
+			Link:        link,
+			Summary:     a.Preview,
+			ContentHTML: contentHTML,
+			Published:   published,
+			Updated:     updated,
+		}
+		if a.Author != nil {
+			item.Author = authorName(a.Author)
+		}
+		for _, tag := range a.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	// 没有文章时以生成时间作为更新时间
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+
+	var body []byte
+	var err error
+	switch format {
+	case FeedFormatRSS:
+		body, err = utils.RenderRSS(feed)
+	case FeedFormatAtom:
+		body, err = utils.RenderAtom(feed)
+	case FeedFormatJSON:
+		body, err = utils.RenderJSONFeed(feed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("生成订阅源失败: %v", err)
+	}
+
+	return &FeedResult{
+		Body:         string(body),
+		ContentType:  feedFormats[format].contentType,
+		ETag:         utils.ContentETag(body),
+		LastModified: feed.Updated,
+	}, nil
+}
+
+// clearFeedCache 清除所有订阅源缓存
+func (s *FeedService) clearFeedCache() {
+	keys, err := global.RedisDB.Keys(feedCtxRedis, global.CacheKeyFeed+":*").Result()
+	if err != nil {
+		fmt.Printf("获取订阅源缓存键失败: %v\n", err)
+		return
+	}
+	if len(keys) > 0 {
+		if err := global.RedisDB.Del(feedCtxRedis, keys...).Err(); err != nil {
+			fmt.Printf("清除订阅源缓存失败: %v\n", err)
+		}
+	}
+}
+
+// cacheKey 订阅源缓存键
+func (s *FeedService) cacheKey(format string, scope FeedScope) string {
+	switch {
+	case scope.Tag != "":
+		return fmt.Sprintf("%s:%s:tag:%s", global.CacheKeyFeed, format, scope.Tag)
+	case scope.AuthorID != 0:
+		return fmt.Sprintf("%s:%s:author:%d", global.CacheKeyFeed, format, scope.AuthorID)
+	default:
+		return fmt.Sprintf("%s:%s:all", global.CacheKeyFeed, format)
+	}
+}
+
+// feedPath 订阅源的访问路径，与路由保持一致
+func (s *FeedService) feedPath(format string, scope FeedScope) string {
+	file := feedFormats[format].file
+	switch {
+	case scope.Tag != "":
+		return fmt.Sprintf("/tag/%s/%s", url.PathEscape(scope.Tag), file)
+	case scope.AuthorID != 0:
+		return fmt.Sprintf("/author/%d/%s", scope.AuthorID, file)
+	default:
+		return "/" + file
+	}
+}
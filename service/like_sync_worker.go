@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"go_test/config"
+	"log"
+	"sync"
+	"time"
+)
+
+// LikeSyncWorker 点赞回写任务，周期性地把Redis中有变更的点赞数据批量写入MySQL
+type LikeSyncWorker struct {
+	likeService *ArticleLikeService
+	interval    time.Duration
+	batchSize   int
+	wg          sync.WaitGroup
+}
+
+func NewLikeSyncWorker() *LikeSyncWorker {
+	interval := 5 * time.Second
+	batchSize := 100
+	if likeConfig := config.GetLikeConfig(); likeConfig != nil {
+		if likeConfig.FlushIntervalSeconds > 0 {
+			interval = time.Duration(likeConfig.FlushIntervalSeconds) * time.Second
+		}
+		if likeConfig.BatchSize > 0 {
+			batchSize = likeConfig.BatchSize
+		}
+	}
+
+	return &LikeSyncWorker{
+		likeService: NewArticleLikeService(),
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Start 启动回写协程，ctx取消时执行最后一次回写后退出
+func (w *LikeSyncWorker) Start(ctx context.Context) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		if err := w.likeService.MigrateLegacyLikeCounters(ctx); err != nil {
+			log.Printf("迁移旧点赞计数失败: %v", err)
+		}
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.runOnce(ctx)
+
+			select {
+			case <-ctx.Done():
+				// 使用独立的context完成最后一次回写，避免退出时丢失未回写的点赞
+				w.runOnce(context.Background())
+				log.Println("点赞回写任务已停止")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait 等待回写协程退出，用于优雅关闭
+func (w *LikeSyncWorker) Wait() {
+	w.wg.Wait()
+}
+
+// runOnce 确认Redis中的点赞数据完整后执行一次回写
+func (w *LikeSyncWorker) runOnce(ctx context.Context) {
+	loaded, err := w.likeService.EnsureLikesLoaded(ctx, 10*w.interval)
+	if err != nil {
+		log.Printf("加载点赞数据失败: %v", err)
+		return
+	}
+	if !loaded {
+		return
+	}
+
+	count, err := w.likeService.FlushDirtyLikes(ctx, w.batchSize)
+	if err != nil {
+		log.Printf("点赞回写失败: %v", err)
+	}
+	if count > 0 {
+		log.Printf("点赞回写完成，同步%d篇文章", count)
+	}
+}
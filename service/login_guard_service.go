@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go_test/config"
+	"go_test/global"
+	"go_test/model"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// 登录失败计数的维度
+const (
+	loginSubjectUser = "user"
+	loginSubjectIP   = "ip"
+)
+
+var loginGuardCtx = context.Background()
+
+// acquireLoginScript 检查锁定和等待期，并预占一次尝试（失败计数加一）
+// 检查和计数在同一个脚本中完成，并发请求无法同时通过检查；超过上限的预占会退回并拒绝
+// KEYS: 用户锁定、IP锁定、用户等待期、用户失败计数、IP失败计数
+// ARGV: 用户上限、IP上限、统计窗口（毫秒）、是否统计IP
+// 返回 {状态, 剩余毫秒或用户计数, IP计数}，状态 0通过 1用户锁定 2IP锁定 3等待期 4超过上限
+const acquireLoginScript = `
+for i = 1, 3 do
+	local ttl = redis.call("PTTL", KEYS[i])
+	if ttl > 0 then
+		return {i, ttl, 0}
+	end
+end
+local userFails = redis.call("INCR", KEYS[4])
+if userFails == 1 then
+	redis.call("PEXPIRE", KEYS[4], ARGV[3])
+end
+if userFails > tonumber(ARGV[1]) then
+	redis.call("DECR", KEYS[4])
+	return {4, 0, 0}
+end
+local ipFails = 0
+if ARGV[4] == "1" then
+	ipFails = redis.call("INCR", KEYS[5])
+	if ipFails == 1 then
+		redis.call("PEXPIRE", KEYS[5], ARGV[3])
+	end
+	if ipFails > tonumber(ARGV[2]) then
+		redis.call("DECR", KEYS[4])
+		redis.call("DECR", KEYS[5])
+		return {4, 0, 0}
+	end
+end
+return {0, userFails, ipFails}
+`
+
+// releaseLoginScript 退回预占的尝试，计数已过期时不处理，避免产生没有过期时间的负数
+const releaseLoginScript = `
+for i = 1, #KEYS do
+	if tonumber(redis.call("GET", KEYS[i]) or "0") > 0 then
+		redis.call("DECR", KEYS[i])
+	end
+end
+return 0
+`
+
+// LoginGuardService 登录防暴力破解：按用户名和IP统计失败次数，失败后按指数退避等待，超过阈值临时锁定
+// 校验密码前先通过 Acquire 原子地预占一次尝试，并发请求最多只有阈值内的次数能进入密码校验
+// 用户名不存在时同样计数，锁定提示不会暴露用户名是否存在；Redis不可用时降级放行
+type LoginGuardService struct{}
+
+func NewLoginGuardService() *LoginGuardService {
+	return &LoginGuardService{}
+}
+
+// LoginAttempt 一次已预占的登录尝试，校验失败调用 Fail，未失败调用 Release
+type LoginAttempt struct {
+	guard     *LoginGuardService
+	username  string
+	clientIP  string
+	userFails int64 // 含本次在内的失败计数，为0表示Redis不可用未计数
+	ipFails   int64
+}
+
+// Acquire 登录前检查用户名和IP是否处于锁定或等待期，通过时预占一次尝试
+func (s *LoginGuardService) Acquire(username, clientIP string) (*LoginAttempt, error) {
+	cfg := s.config()
+	trackIP := "0"
+	if clientIP != "" {
+		trackIP = "1"
+	}
+	keys := []string{
+		s.key(global.CacheKeyLoginLock, loginSubjectUser, username),
+		s.key(global.CacheKeyLoginLock, loginSubjectIP, clientIP),
+		s.key(global.CacheKeyLoginBackoff, loginSubjectUser, username),
+		s.key(global.CacheKeyLoginFail, loginSubjectUser, username),
+		s.key(global.CacheKeyLoginFail, loginSubjectIP, clientIP),
+	}
+	window := time.Duration(cfg.WindowMinutes) * time.Minute
+
+	attempt := &LoginAttempt{guard: s, username: username, clientIP: clientIP}
+	result, err := global.RedisDB.Eval(loginGuardCtx, acquireLoginScript, keys,
+		cfg.MaxAttempts, cfg.IPMaxAttempts, window.Milliseconds(), trackIP).Int64Slice()
+	if err != nil || len(result) != 3 {
+		log.Printf("检查登录锁定状态失败: %v", err)
+		return attempt, nil
+	}
+
+	ttl := time.Duration(result[1]) * time.Millisecond
+	switch result[0] {
+	case 1:
+		return nil, fmt.Errorf("账户已被临时锁定，请%d分钟后重试", ceilMinutes(ttl))
+	case 2:
+		return nil, fmt.Errorf("登录失败次数过多，请%d分钟后重试", ceilMinutes(ttl))
+	case 3:
+		return nil, fmt.Errorf("登录尝试过于频繁，请%d秒后重试", int(math.Ceil(ttl.Seconds())))
+	case 4:
+		return nil, fmt.Errorf("登录尝试过于频繁，请稍后重试")
+	}
+	attempt.userFails = result[1]
+	attempt.ipFails = result[2]
+	return attempt, nil
+}
+
+// Fail 本次尝试校验失败：用户名进入等待期，达到阈值时锁定用户名或IP
+func (a *LoginAttempt) Fail() {
+	if a.userFails == 0 {
+		return
+	}
+	cfg := a.guard.config()
+	lock := time.Duration(cfg.LockMinutes) * time.Minute
+
+	lockUser, delay := loginPenalty(cfg, a.userFails)
+	if lockUser {
+		a.guard.lock(loginSubjectUser, a.username, lock)
+		log.Printf("用户名%s连续登录失败%d次，已临时锁定", a.username, a.userFails)
+	} else if delay > 0 {
+		if err := global.RedisDB.Set(loginGuardCtx, a.guard.key(global.CacheKeyLoginBackoff, loginSubjectUser, a.username), 1, delay).Err(); err != nil {
+			log.Printf("设置登录等待期失败: %v", err)
+		}
+	}
+
+	if a.clientIP != "" && a.ipFails >= int64(cfg.IPMaxAttempts) {
+		a.guard.lock(loginSubjectIP, a.clientIP, lock)
+		log.Printf("IP %s登录失败%d次，已临时封禁", a.clientIP, a.ipFails)
+	}
+}
+
+// Release 本次尝试没有失败（如密码正确），退回预占的计数，之前的失败记录保留
+func (a *LoginAttempt) Release() {
+	if a.userFails == 0 {
+		return
+	}
+	keys := []string{a.guard.key(global.CacheKeyLoginFail, loginSubjectUser, a.username)}
+	if a.clientIP != "" {
+		keys = append(keys, a.guard.key(global.CacheKeyLoginFail, loginSubjectIP, a.clientIP))
+	}
+	if err := global.RedisDB.Eval(loginGuardCtx, releaseLoginScript, keys).Err(); err != nil {
+		log.Printf("退回登录尝试计数失败: %v", err)
+	}
+}
+
+// loginPenalty 第fails次失败后的处理：达到阈值时锁定，否则等待 base*2^(fails-1) 秒（不超过上限）
+func loginPenalty(cfg config.LoginConfig, fails int64) (lock bool, backoff time.Duration) {
+	if fails >= int64(cfg.MaxAttempts) {
+		return true, 0
+	}
+	if cfg.BackoffBaseSeconds <= 0 || fails <= 0 {
+		return false, 0
+	}
+	maxDelay := time.Duration(cfg.BackoffMaxSeconds) * time.Second
+	// 避免移位溢出，超过上限的次数直接取上限
+	if fails > 32 {
+		return false, maxDelay
+	}
+	delay := time.Duration(cfg.BackoffBaseSeconds) * time.Second << uint(fails-1)
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	return false, delay
+}
+
+// Reset 登录成功后清除用户名的失败记录；IP的计数保留，避免用自己的账户反复重置
+func (s *LoginGuardService) Reset(username string) {
+	if err := s.clear(loginSubjectUser, username); err != nil {
+		log.Printf("清除登录失败记录失败: %v", err)
+	}
+}
+
+// UnlockUser 管理员解除用户的登录锁定，clientIP不为空时同时解除该IP的封禁
+func (s *LoginGuardService) UnlockUser(userID uint, clientIP string) error {
+	var user model.User
+	if err := global.DB.Select("id", "username").First(&user, userID).Error; err != nil {
+		return fmt.Errorf("用户不存在")
+	}
+	if err := s.clear(loginSubjectUser, user.Username); err != nil {
+		return fmt.Errorf("解除锁定失败: %v", err)
+	}
+	if clientIP != "" {
+		if err := s.clear(loginSubjectIP, clientIP); err != nil {
+			return fmt.Errorf("解除锁定失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// lock 锁定并重置失败计数，锁定到期后重新计数
+func (s *LoginGuardService) lock(subject, value string, ttl time.Duration) {
+	pipe := global.RedisDB.TxPipeline()
+	pipe.Set(loginGuardCtx, s.key(global.CacheKeyLoginLock, subject, value), 1, ttl)
+	pipe.Del(loginGuardCtx, s.key(global.CacheKeyLoginFail, subject, value), s.key(global.CacheKeyLoginBackoff, subject, value))
+	if _, err := pipe.Exec(loginGuardCtx); err != nil {
+		log.Printf("设置登录锁定失败: %v", err)
+	}
+}
+
+func (s *LoginGuardService) clear(subject, value string) error {
+	return global.RedisDB.Del(loginGuardCtx,
+		s.key(global.CacheKeyLoginFail, subject, value),
+		s.key(global.CacheKeyLoginLock, subject, value),
+		s.key(global.CacheKeyLoginBackoff, subject, value),
+	).Err()
+}
+
+// key 用户名不区分大小写，与数据库的比较规则一致
+func (s *LoginGuardService) key(prefix, subject, value string) string {
+	if subject == loginSubjectUser {
+		value = strings.ToLower(value)
+	}
+	return fmt.Sprintf("%s:%s:%s", prefix, subject, value)
+}
+
+// config 登录保护配置，未配置的项使用默认值
+func (s *LoginGuardService) config() config.LoginConfig {
+	cfg := config.LoginConfig{}
+	if c := config.GetLoginConfig(); c != nil {
+		cfg = *c
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.IPMaxAttempts <= 0 {
+		cfg.IPMaxAttempts = 20
+	}
+	if cfg.WindowMinutes <= 0 {
+		cfg.WindowMinutes = 15
+	}
+	if cfg.LockMinutes <= 0 {
+		cfg.LockMinutes = 15
+	}
+	if cfg.BackoffMaxSeconds <= 0 {
+		cfg.BackoffMaxSeconds = 60
+	}
+	return cfg
+}
+
+// ceilMinutes 剩余时间向上取整到分钟
+func ceilMinutes(d time.Duration) int {
+	return int(math.Ceil(d.Minutes()))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"go_test/config"
+	"go_test/global"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+// setupLoginGuard 使用内存Redis，未加载配置时登录保护使用默认值（用户名5次、IP 20次）
+func setupLoginGuard(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	global.RedisDB = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { global.RedisDB.Close() })
+	return mr
+}
+
+func TestLoginPenalty(t *testing.T) {
+	cfg := config.LoginConfig{MaxAttempts: 5, BackoffBaseSeconds: 2, BackoffMaxSeconds: 10}
+	tests := []struct {
+		name    string
+		cfg     config.LoginConfig
+		fails   int64
+		lock    bool
+		backoff time.Duration
+	}{
+		{"第一次失败", cfg, 1, false, 2 * time.Second},
+		{"等待时间翻倍", cfg, 3, false, 8 * time.Second},
+		{"等待时间不超过上限", cfg, 4, false, 10 * time.Second},
+		{"达到阈值锁定", cfg, 5, true, 0},
+		{"超过阈值锁定", cfg, 8, true, 0},
+		{"未配置等待基数", config.LoginConfig{MaxAttempts: 5}, 3, false, 0},
+		{"次数过大不溢出", config.LoginConfig{MaxAttempts: 100, BackoffBaseSeconds: 1, BackoffMaxSeconds: 60}, 70, false, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lock, backoff := loginPenalty(tt.cfg, tt.fails)
+			if lock != tt.lock || backoff != tt.backoff {
+				t.Errorf("loginPenalty(%d) = (%v, %v), want (%v, %v)", tt.fails, lock, backoff, tt.lock, tt.backoff)
+			}
+		})
+	}
+}
+
+func TestLoginGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(guard *LoginGuardService) error
+		wantErr string
+	}{
+		{
+			name: "连续失败达到阈值后锁定用户名",
+			run: func(guard *LoginGuardService) error {
+				for i := 0; i < 5; i++ {
+					attempt, err := guard.Acquire("Alice", "10.0.0.1")
+					if err != nil {
+						return err
+					}
+					attempt.Fail()
+				}
+				// 用户名不区分大小写，换IP也无法绕过
+				_, err := guard.Acquire("alice", "10.0.0.2")
+				return err
+			},
+			wantErr: "账户已被临时锁定",
+		},
+		{
+			name: "登录成功后清除失败记录",
+			run: func(guard *LoginGuardService) error {
+				for i := 0; i < 4; i++ {
+					attempt, err := guard.Acquire("bob", "10.0.0.1")
+					if err != nil {
+						return err
+					}
+					attempt.Fail()
+				}
+				guard.Reset("bob")
+				for i := 0; i < 4; i++ {
+					attempt, err := guard.Acquire("bob", "10.0.0.1")
+					if err != nil {
+						return err
+					}
+					attempt.Fail()
+				}
+				return nil
+			},
+		},
+		{
+			name: "未失败的尝试退回计数",
+			run: func(guard *LoginGuardService) error {
+				for i := 0; i < 30; i++ {
+					attempt, err := guard.Acquire("carol", "10.0.0.1")
+					if err != nil {
+						return err
+					}
+					attempt.Release()
+				}
+				return nil
+			},
+		},
+		{
+			name: "同一IP失败过多时封禁",
+			run: func(guard *LoginGuardService) error {
+				for i := 0; i < 20; i++ {
+					attempt, err := guard.Acquire("user"+string(rune('a'+i)), "10.0.0.9")
+					if err != nil {
+						return err
+					}
+					attempt.Fail()
+				}
+				_, err := guard.Acquire("someone", "10.0.0.9")
+				return err
+			},
+			wantErr: "登录失败次数过多",
+		},
+		{
+			name: "等待期内拒绝",
+			run: func(guard *LoginGuardService) error {
+				global.RedisDB.Set(loginGuardCtx, guard.key(global.CacheKeyLoginBackoff, loginSubjectUser, "dave"), 1, 8*time.Second)
+				_, err := guard.Acquire("dave", "10.0.0.1")
+				return err
+			},
+			wantErr: "请8秒后重试",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupLoginGuard(t)
+			err := tt.run(NewLoginGuardService())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// 并发请求同时预占，最多只有阈值内的次数能进入密码校验
+func TestLoginGuardConcurrentAcquire(t *testing.T) {
+	setupLoginGuard(t)
+	guard := NewLoginGuardService()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		granted int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := guard.Acquire("eve", "10.0.0.1"); err == nil {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if granted != 5 {
+		t.Fatalf("granted = %d, want 5", granted)
+	}
+}
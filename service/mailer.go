@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/utils"
+	"log"
+)
+
+// 邮件驱动
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverLog  = "log"
+)
+
+// mailer 当前使用的邮件发送实现，由InitMailer根据配置创建；为nil表示未配置，发送邮件的接口会返回错误
+var mailer utils.Mailer
+
+// errMailerNotConfigured 未配置邮件发送时，需要发邮件的接口返回的错误
+const errMailerNotConfigured = "邮件服务未配置，请联系管理员"
+
+// InitMailer 根据配置初始化邮件发送实现，启动时调用
+// 默认使用SMTP；未配置SMTP服务器时不影响启动，只在需要发邮件时报错；只有显式配置 driver: log 才打印到日志
+func InitMailer() error {
+	cfg := config.MailConfig{}
+	if c := config.GetMailConfig(); c != nil {
+		cfg = *c
+	}
+
+	switch cfg.Driver {
+	case MailDriverSMTP, "":
+		if cfg.Host == "" || cfg.From == "" {
+			mailer = nil
+			log.Println("未配置mail.host和mail.from，邮箱验证和找回密码不可用")
+			return nil
+		}
+		port := cfg.Port
+		if port == 0 {
+			port = 587
+		}
+		mailer = &utils.SMTPMailer{
+			Host:        cfg.Host,
+			Port:        port,
+			Username:    cfg.Username,
+			Password:    cfg.Password,
+			From:        cfg.From,
+			ImplicitTLS: port == 465,
+		}
+	case MailDriverLog:
+		log.Println("邮件驱动为log，邮件只打印到日志，不会真正发送")
+		mailer = &utils.LogMailer{From: cfg.From, Dir: cfg.Dir}
+	default:
+		return fmt.Errorf("不支持的邮件驱动: %s", cfg.Driver)
+	}
+	return nil
+}
+
+// mailerReady 需要发送邮件的操作先检查是否已配置邮件发送
+func mailerReady() error {
+	if mailer == nil {
+		return fmt.Errorf(errMailerNotConfigured)
+	}
+	return nil
+}
+
+// sendMailAsync 异步发送邮件，失败只记录日志；调用方的响应时间不受邮件服务影响
+func sendMailAsync(mail utils.Mail) {
+	go func() {
+		if err := mailer.Send(mail); err != nil {
+			log.Printf("发送邮件到%s失败: %v", mail.To, err)
+		}
+	}()
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/global"
+	"go_test/model"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// SearchDocument 被索引的文章内容
+type SearchDocument struct {
+	ID      uint
+	Title   string
+	Content string
+}
+
+// SearchHit 搜索命中的文章及相关度得分
+type SearchHit struct {
+	ID    uint
+	Score float64
+}
+
+// SearchEngine 文章全文搜索引擎
+// 查询语法：空白分隔的每一项都必须命中（标题或正文），双引号括起来的内容按短语匹配；
+// 结果按相关度倒序，标题命中的权重高于正文
+type SearchEngine interface {
+	// Index 新增或更新文章索引
+	Index(doc SearchDocument) error
+	// Remove 删除文章索引
+	Remove(ids ...uint) error
+	// Search 搜索文章，最多返回limit条
+	Search(query string, limit int) ([]SearchHit, error)
+}
+
+// ScopedSearcher 能把列表筛选条件并入搜索查询的引擎，总数和分页都是精确的
+// 未实现该接口的引擎先取最多searchMaxResults条命中，再在命中结果上筛选分页
+type ScopedSearcher interface {
+	// SearchScoped 在scope的条件下搜索，返回第offset条起的最多limit条命中及命中总数
+	SearchScoped(query string, scope *gorm.DB, offset, limit int) ([]SearchHit, int64, error)
+}
+
+// 搜索引擎类型
+const (
+	SearchEngineMySQL  = "mysql"  // MySQL FULLTEXT + ngram分词
+	SearchEngineMemory = "memory" // 进程内倒排索引，适合测试和小规模部署
+)
+
+// searchEngine 当前使用的搜索引擎，由InitSearchEngine初始化
+var searchEngine SearchEngine
+
+// searchMaxResults 未实现ScopedSearcher的引擎单次搜索最多返回的命中数，分页在命中结果上进行
+var searchMaxResults = 1000
+
+// InitSearchEngine 根据配置初始化搜索引擎，启动时调用
+func InitSearchEngine() error {
+	engine := SearchEngineMySQL
+	titleWeight, contentWeight := 3.0, 1.0
+	if searchConfig := config.GetSearchConfig(); searchConfig != nil {
+		if searchConfig.Engine != "" {
+			engine = searchConfig.Engine
+		}
+		if searchConfig.TitleWeight > 0 {
+			titleWeight = searchConfig.TitleWeight
+		}
+		if searchConfig.ContentWeight > 0 {
+			contentWeight = searchConfig.ContentWeight
+		}
+		if searchConfig.MaxResults > 0 {
+			searchMaxResults = searchConfig.MaxResults
+		}
+	}
+
+	switch engine {
+	case SearchEngineMySQL:
+		mysqlEngine := NewMySQLSearchEngine(global.DB, titleWeight, contentWeight)
+		if err := mysqlEngine.EnsureIndexes(); err != nil {
+			return err
+		}
+		searchEngine = mysqlEngine
+	case SearchEngineMemory:
+		memoryEngine := NewMemorySearchEngine(titleWeight, contentWeight)
+		if err := loadMemorySearchIndex(memoryEngine); err != nil {
+			return err
+		}
+		searchEngine = memoryEngine
+	default:
+		return fmt.Errorf("不支持的搜索引擎: %s", engine)
+	}
+
+	log.Printf("搜索引擎已初始化: %s", engine)
+	return nil
+}
+
+// loadMemorySearchIndex 将所有未删除的文章加载到内存索引
+func loadMemorySearchIndex(engine *MemorySearchEngine) error {
+	var articles []model.Article
+	total := 0
+	err := global.DB.Select("id", "title", "content").FindInBatches(&articles, 500, func(tx *gorm.DB, batch int) error {
+		for _, a := range articles {
+			if err := engine.Index(SearchDocument{ID: a.ID, Title: a.Title, Content: a.Content}); err != nil {
+				return err
+			}
+		}
+		total += len(articles)
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("加载搜索索引失败: %v", err)
+	}
+
+	log.Printf("已加载%d篇文章到内存搜索索引", total)
+	return nil
+}
+
+// indexArticle 文章内容变更后更新搜索索引，失败只记录日志
+func indexArticle(a model.Article) {
+	if searchEngine == nil {
+		return
+	}
+	if err := searchEngine.Index(SearchDocument{ID: a.ID, Title: a.Title, Content: a.Content}); err != nil {
+		log.Printf("更新文章%d搜索索引失败: %v", a.ID, err)
+	}
+}
+
+// removeArticlesFromIndex 文章删除后移除搜索索引，失败只记录日志
+func removeArticlesFromIndex(ids []uint) {
+	if searchEngine == nil {
+		return
+	}
+	if err := searchEngine.Remove(ids...); err != nil {
+		log.Printf("删除文章搜索索引失败: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"go_test/config"
+	"go_test/dto"
+	"go_test/model"
+	"go_test/utils"
+)
+
+// 搜索结果高亮方式
+const (
+	HighlightMark     = "mark"     // <mark>标签，其余文本做HTML转义
+	HighlightMarkdown = "markdown" // Markdown加粗
+)
+
+// 摘要默认参数
+const (
+	defaultSnippetRadius = 40
+	defaultMaxSnippets   = 3
+)
+
+// resolveHighlight 确定高亮方式，未指定时取配置，默认使用<mark>
+func resolveHighlight(style string) string {
+	if style == "" {
+		if searchConfig := config.GetSearchConfig(); searchConfig != nil {
+			style = searchConfig.Highlight
+		}
+	}
+	if style == HighlightMarkdown {
+		return HighlightMarkdown
+	}
+	return HighlightMark
+}
+
+// highlightMarker 高亮方式对应的标记
+func highlightMarker(style string) utils.HighlightMarker {
+	if style == HighlightMarkdown {
+		return utils.HighlightMarkdown
+	}
+	return utils.HighlightMarkHTML
+}
+
+// toSearchResultVO 转换搜索结果：标题高亮所有命中，正文只返回命中位置附近的摘要
+func (s *ArticleService) toSearchResultVO(a model.Article, terms []string, style string) dto.ArticleVO {
+	radius, maxSnippets := defaultSnippetRadius, defaultMaxSnippets
+	if searchConfig := config.GetSearchConfig(); searchConfig != nil {
+		if searchConfig.SnippetRadius > 0 {
+			radius = searchConfig.SnippetRadius
+		}
+		if searchConfig.MaxSnippets > 0 {
+			maxSnippets = searchConfig.MaxSnippets
+		}
+	}
+	marker := highlightMarker(style)
+
+	vo := s.toArticleVO(a)
+	title, titleMatched := utils.HighlightTerms(a.Title, terms, marker)
+	vo.Title = title
+	vo.Snippet = utils.BuildSnippet(a.Content, terms, radius, maxSnippets, marker)
+	vo.Content = ""
+	vo.ContentHTML = ""
+	vo.TOC = nil
+
+	vo.MatchedFields = make([]string, 0, 2)
+	if titleMatched {
+		vo.MatchedFields = append(vo.MatchedFields, "title")
+	}
+	if utils.MatchTerms(a.Content, terms) {
+		vo.MatchedFields = append(vo.MatchedFields, "content")
+	}
+	return vo
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"go_test/utils"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25词频饱和参数
+const bm25K1 = 1.2
+
+// memoryPostings 某个词在一篇文章中出现的位置
+type memoryPostings struct {
+	title   []int
+	content []int
+}
+
+// MemorySearchEngine 进程内倒排索引搜索引擎
+// 每项查询按短语匹配（分词后位置连续），所有项都必须命中；得分为BM25风格的加权词频
+type MemorySearchEngine struct {
+	mu            sync.RWMutex
+	index         map[string]map[uint]*memoryPostings // 词 -> 文章ID -> 出现位置
+	docTokens     map[uint][]string                   // 文章ID -> 包含的词，用于删除索引
+	titleWeight   float64
+	contentWeight float64
+}
+
+func NewMemorySearchEngine(titleWeight, contentWeight float64) *MemorySearchEngine {
+	return &MemorySearchEngine{
+		index:         make(map[string]map[uint]*memoryPostings),
+		docTokens:     make(map[uint][]string),
+		titleWeight:   titleWeight,
+		contentWeight: contentWeight,
+	}
+}
+
+func (e *MemorySearchEngine) Index(doc SearchDocument) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.remove(doc.ID)
+
+	postings := make(map[string]*memoryPostings)
+	get := func(text string) *memoryPostings {
+		p, ok := postings[text]
+		if !ok {
+			p = &memoryPostings{}
+			postings[text] = p
+		}
+		return p
+	}
+	for _, token := range utils.TokenizeForIndex(doc.Title) {
+		p := get(token.Text)
+		p.title = append(p.title, token.Position)
+	}
+	for _, token := range utils.TokenizeForIndex(doc.Content) {
+		p := get(token.Text)
+		p.content = append(p.content, token.Position)
+	}
+
+	tokens := make([]string, 0, len(postings))
+	for text, p := range postings {
+		docs, ok := e.index[text]
+		if !ok {
+			docs = make(map[uint]*memoryPostings)
+			e.index[text] = docs
+		}
+		docs[doc.ID] = p
+		tokens = append(tokens, text)
+	}
+	e.docTokens[doc.ID] = tokens
+	return nil
+}
+
+func (e *MemorySearchEngine) Remove(ids ...uint) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, id := range ids {
+		e.remove(id)
+	}
+	return nil
+}
+
+// remove 删除文章索引，调用方需持有写锁
+func (e *MemorySearchEngine) remove(id uint) {
+	for _, text := range e.docTokens[id] {
+		docs := e.index[text]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(e.index, text)
+		}
+	}
+	delete(e.docTokens, id)
+}
+
+func (e *MemorySearchEngine) Search(query string, limit int) ([]SearchHit, error) {
+	terms := utils.ParseSearchQuery(query)
+	if len(terms) == 0 {
+		return []SearchHit{}, nil
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	total := float64(len(e.docTokens))
+	scores := make(map[uint]float64)
+	for i, term := range terms {
+		tokens := utils.Tokenize(term)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		// 每篇文章中该短语在标题和正文的出现次数
+		titleFreq, contentFreq := e.matchPhrase(tokens)
+		matched := make(map[uint]bool, len(titleFreq)+len(contentFreq))
+		for id := range titleFreq {
+			matched[id] = true
+		}
+		for id := range contentFreq {
+			matched[id] = true
+		}
+		if len(matched) == 0 {
+			return []SearchHit{}, nil
+		}
+
+		idf := math.Log(1 + (total-float64(len(matched))+0.5)/(float64(len(matched))+0.5))
+		next := make(map[uint]float64, len(matched))
+		for id := range matched {
+			// 第一项之后只保留前面各项都命中的文章
+			prev, ok := scores[id]
+			if i > 0 && !ok {
+				continue
+			}
+			next[id] = prev + idf*(e.titleWeight*saturate(titleFreq[id])+e.contentWeight*saturate(contentFreq[id]))
+		}
+		scores = next
+		if len(scores) == 0 {
+			return []SearchHit{}, nil
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, SearchHit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// matchPhrase 统计短语在各文章标题和正文中的出现次数，调用方需持有读锁
+func (e *MemorySearchEngine) matchPhrase(tokens []utils.Token) (map[uint]int, map[uint]int) {
+	titleFreq := make(map[uint]int)
+	contentFreq := make(map[uint]int)
+
+	first := e.index[tokens[0].Text]
+	for id, p := range first {
+		if n := e.countPhrase(id, tokens, p.title, func(p *memoryPostings) []int { return p.title }); n > 0 {
+			titleFreq[id] = n
+		}
+		if n := e.countPhrase(id, tokens, p.content, func(p *memoryPostings) []int { return p.content }); n > 0 {
+			contentFreq[id] = n
+		}
+	}
+	return titleFreq, contentFreq
+}
+
+// countPhrase 从短语首词的每个出现位置开始，检查后续词是否依次出现在相邻位置
+func (e *MemorySearchEngine) countPhrase(id uint, tokens []utils.Token, starts []int, field func(*memoryPostings) []int) int {
+	count := 0
+	for _, start := range starts {
+		matched := true
+		for offset := 1; offset < len(tokens); offset++ {
+			p, ok := e.index[tokens[offset].Text][id]
+			if !ok || !containsPosition(field(p), start+offset) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			count++
+		}
+	}
+	return count
+}
+
+// containsPosition 位置列表按升序排列，二分查找
+func containsPosition(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
+
+// saturate 词频饱和，出现次数越多增益越小
+func saturate(freq int) float64 {
+	if freq == 0 {
+		return 0
+	}
+	f := float64(freq)
+	return f * (bm25K1 + 1) / (f + bm25K1)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestMemorySearchEngine(t *testing.T) *MemorySearchEngine {
+	t.Helper()
+	engine := NewMemorySearchEngine(3, 1)
+	docs := []SearchDocument{
+		{ID: 1, Title: "MySQL数据库入门", Content: "介绍关系型数据库的基本概念"},
+		{ID: 2, Title: "Go语言并发", Content: "goroutine和channel，顺便提到数据库连接池"},
+		{ID: 3, Title: "Redis缓存", Content: "缓存与数据库的一致性"},
+		{ID: 4, Title: "全文搜索", Content: "full text search with ngram"},
+	}
+	for _, doc := range docs {
+		if err := engine.Index(doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return engine
+}
+
+func hitIDs(hits []SearchHit) []uint {
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestMemorySearchEngine(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []uint
+	}{
+		{"标题命中排在前面", "数据库", 0, []uint{1, 3, 2}},
+		{"单个汉字也能命中", "库", 0, []uint{1, 3, 2}},
+		{"所有项都要命中", "数据库 缓存", 0, []uint{3}},
+		{"英文不区分大小写", "MYSQL", 0, []uint{1}},
+		{"短语要求位置相邻", `"full text"`, 0, []uint{4}},
+		{"短语顺序不对不命中", `"text full"`, 0, []uint{}},
+		{"没有命中", "kubernetes", 0, []uint{}},
+		{"限制返回条数", "数据库", 2, []uint{1, 3}},
+	}
+	engine := newTestMemorySearchEngine(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := engine.Search(tt.query, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemorySearchEngineUpdateAndRemove(t *testing.T) {
+	engine := newTestMemorySearchEngine(t)
+
+	// 更新后旧内容不再命中
+	if err := engine.Index(SearchDocument{ID: 1, Title: "PostgreSQL", Content: "索引"}); err != nil {
+		t.Fatal(err)
+	}
+	hits, _ := engine.Search("mysql", 0)
+	if got := hitIDs(hits); len(got) != 0 {
+		t.Errorf("after update: Search(mysql) = %v, want []", got)
+	}
+
+	if err := engine.Remove(3); err != nil {
+		t.Fatal(err)
+	}
+	hits, _ = engine.Search("数据库", 0)
+	if got, want := hitIDs(hits), []uint{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after remove: Search(数据库) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"go_test/model"
+	"go_test/utils"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MySQL全文索引名称
+const (
+	fulltextIndexTitleContent = "ft_articles_title_content" // 用于筛选：所有词都要在标题或正文中出现
+	fulltextIndexTitle        = "ft_articles_title"         // 用于计算标题相关度
+	fulltextIndexContent      = "ft_articles_content"       // 用于计算正文相关度
+)
+
+// MySQLSearchEngine 基于MySQL FULLTEXT索引（ngram分词）的搜索引擎
+// 索引由MySQL随文章表自动维护，Index/Remove无需处理
+type MySQLSearchEngine struct {
+	db            *gorm.DB
+	titleWeight   float64
+	contentWeight float64
+}
+
+func NewMySQLSearchEngine(db *gorm.DB, titleWeight, contentWeight float64) *MySQLSearchEngine {
+	return &MySQLSearchEngine{
+		db:            db,
+		titleWeight:   titleWeight,
+		contentWeight: contentWeight,
+	}
+}
+
+// EnsureIndexes 创建缺失的全文索引
+func (e *MySQLSearchEngine) EnsureIndexes() error {
+	indexes := []struct {
+		name    string
+		columns string
+	}{
+		{fulltextIndexTitleContent, "title, content"},
+		{fulltextIndexTitle, "title"},
+		{fulltextIndexContent, "content"},
+	}
+
+	for _, index := range indexes {
+		if e.db.Migrator().HasIndex(&model.Article{}, index.name) {
+			continue
+		}
+		sql := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON articles (%s) WITH PARSER ngram", index.name, index.columns)
+		if err := e.db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("创建全文索引%s失败: %v", index.name, err)
+		}
+	}
+	return nil
+}
+
+func (e *MySQLSearchEngine) Index(doc SearchDocument) error {
+	return nil
+}
+
+func (e *MySQLSearchEngine) Remove(ids ...uint) error {
+	return nil
+}
+
+// Search 使用布尔模式筛选（每一项都以短语形式必须出现），再按标题、正文的加权相关度排序
+func (e *MySQLSearchEngine) Search(query string, limit int) ([]SearchHit, error) {
+	hits, _, err := e.search(query, e.db.Model(&model.Article{}), 0, limit, false)
+	return hits, err
+}
+
+// SearchScoped 在scope的筛选条件（状态、标签、分类）下搜索，总数和分页都由MySQL完成，结果不受searchMaxResults截断
+func (e *MySQLSearchEngine) SearchScoped(query string, scope *gorm.DB, offset, limit int) ([]SearchHit, int64, error) {
+	return e.search(query, scope, offset, limit, true)
+}
+
+func (e *MySQLSearchEngine) search(query string, scope *gorm.DB, offset, limit int, withTotal bool) ([]SearchHit, int64, error) {
+	terms := utils.ParseSearchQuery(query)
+	if len(terms) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	required := make([]string, 0, len(terms))
+	optional := make([]string, 0, len(terms))
+	for _, term := range terms {
+		required = append(required, `+"`+term+`"`)
+		optional = append(optional, `"`+term+`"`)
+	}
+	mustMatch := strings.Join(required, " ")
+	rankMatch := strings.Join(optional, " ")
+
+	filtered := scope.Where("MATCH(articles.title, articles.content) AGAINST(? IN BOOLEAN MODE)", mustMatch)
+
+	var total int64
+	if withTotal {
+		if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, 0, fmt.Errorf("搜索失败: %v", err)
+		}
+		if total == 0 || int64(offset) >= total {
+			return []SearchHit{}, total, nil
+		}
+	}
+
+	var hits []SearchHit
+	err := filtered.Session(&gorm.Session{}).
+		Select("articles.id AS id, MATCH(articles.title) AGAINST(? IN BOOLEAN MODE) * ? + MATCH(articles.content) AGAINST(? IN BOOLEAN MODE) * ? AS score",
+			rankMatch, e.titleWeight, rankMatch, e.contentWeight).
+		Order("score DESC, articles.id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("搜索失败: %v", err)
+	}
+	return hits, total, nil
+}
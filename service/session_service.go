@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"time"
+)
+
+type SessionService struct{}
+
+func NewSessionService() *SessionService {
+	return &SessionService{}
+}
+
+// ListSessions 获取用户当前有效的登录会话，按最近活跃时间倒序，currentSessionID标记当前请求所在会话
+func (s *SessionService) ListSessions(userID uint, currentSessionID string) ([]dto.SessionVO, error) {
+	var sessions []model.UserSession
+	if err := global.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	vos := make([]dto.SessionVO, 0, len(sessions))
+	for _, session := range sessions {
+		vos = append(vos, dto.SessionVO{
+			ID:        session.ID,
+			Device:    session.Device,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			Created:   session.CreatedAt.Format("2006-01-02 15:04:05"),
+			LastSeen:  session.LastSeenAt.Format("2006-01-02 15:04:05"),
+			Expires:   session.ExpiresAt.Format("2006-01-02 15:04:05"),
+			Current:   session.SessionID == currentSessionID,
+		})
+	}
+	return vos, nil
+}
+
+// EndSession 结束用户的指定会话，只能结束自己的会话
+func (s *SessionService) EndSession(userID, id uint) error {
+	var session model.UserSession
+	if err := global.DB.Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).First(&session).Error; err != nil {
+		return fmt.Errorf("未找到该会话")
+	}
+	return NewTokenService().RevokeSession(userID, session.SessionID)
+}
+
+// truncateRunes 按字符截断字符串，防止超出字段长度
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
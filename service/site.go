@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/model"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// currentSiteConfig 读取站点配置并补全默认值
+func currentSiteConfig() config.SiteConfig {
+	site := config.SiteConfig{}
+	if siteConfig := config.GetSiteConfig(); siteConfig != nil {
+		site = *siteConfig
+	}
+	if site.Title == "" {
+		site.Title = config.GetAppConfig().Name
+	}
+	if site.BaseURL == "" {
+		site.BaseURL = fmt.Sprintf("http://localhost:%d", config.GetAppConfig().Port)
+	}
+	site.BaseURL = strings.TrimRight(site.BaseURL, "/")
+	if site.FeedSize <= 0 {
+		site.FeedSize = defaultFeedSize
+	}
+	return site
+}
+
+// articleURL 文章的对外访问地址
+// 配置了 site.article_url 时按模板生成（{slug}为空时用ID代替），否则指向公开的文章接口
+func articleURL(site config.SiteConfig, slug string, id uint) string {
+	idStr := strconv.FormatUint(uint64(id), 10)
+	if site.ArticleURL != "" {
+		key := slug
+		if key == "" {
+			key = idStr
+		}
+		return expandSiteURL(site.BaseURL, site.ArticleURL, strings.NewReplacer("{slug}", url.PathEscape(key), "{id}", idStr))
+	}
+	if slug != "" {
+		return fmt.Sprintf("%s/api/public/article/slug/%s", site.BaseURL, url.PathEscape(slug))
+	}
+	return fmt.Sprintf("%s/api/public/article/%s", site.BaseURL, idStr)
+}
+
+// expandSiteURL 替换URL模板中的占位符，相对路径拼接在base_url之后
+func expandSiteURL(baseURL, template string, replacer *strings.Replacer) string {
+	u := replacer.Replace(template)
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		return u
+	}
+	return baseURL + "/" + strings.TrimLeft(u, "/")
+}
+
+// homeURL 站点首页地址，未配置 site.home_url 时指向公开的文章列表接口
+func homeURL(site config.SiteConfig) string {
+	if site.HomeURL != "" {
+		return expandSiteURL(site.BaseURL, site.HomeURL, strings.NewReplacer())
+	}
+	return site.BaseURL + "/api/public/article"
+}
+
+// tagURL 标签页的对外访问地址，未配置 site.tag_url 时指向按标签筛选的分页接口
+func tagURL(site config.SiteConfig, name string) string {
+	if site.TagURL != "" {
+		return expandSiteURL(site.BaseURL, site.TagURL, strings.NewReplacer("{name}", url.PathEscape(name)))
+	}
+	return fmt.Sprintf("%s/api/public/article/pagination?tag=%s", site.BaseURL, url.QueryEscape(name))
+}
+
+// categoryURL 分类页的对外访问地址，未配置 site.category_url 时指向按分类筛选的分页接口
+func categoryURL(site config.SiteConfig, name string) string {
+	if site.CategoryURL != "" {
+		return expandSiteURL(site.BaseURL, site.CategoryURL, strings.NewReplacer("{name}", url.PathEscape(name)))
+	}
+	return fmt.Sprintf("%s/api/public/article/pagination?category=%s", site.BaseURL, url.QueryEscape(name))
+}
+
+// authorName 作者显示名称，优先使用昵称
+func authorName(user *model.User) string {
+	if user.Nickname != "" {
+		return user.Nickname
+	}
+	return user.Username
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go_test/config"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const (
+	sitemapPagesFile    = "pages-%d.xml"    // 首页、标签页、分类页，从1开始编号
+	sitemapArticlesFile = "articles-%d.xml" // 文章，从1开始编号
+	sitemapLockTTL      = 5 * time.Minute
+)
+
+var sitemapCtxRedis = context.Background()
+
+// sitemapEntry Redis中保存的文章条目
+type sitemapEntry struct {
+	Slug    string `json:"slug"`
+	LastMod int64  `json:"lastmod"`
+}
+
+// SitemapResult 渲染好的站点地图
+type SitemapResult struct {
+	Body         string    `json:"body"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// SitemapService 站点地图
+// 已发布文章的URL和更新时间保存在Redis中，文章变更时增量更新，生成站点地图时不扫描articles表
+type SitemapService struct{}
+
+func NewSitemapService() *SitemapService {
+	return &SitemapService{}
+}
+
+// GetSitemap 获取站点地图，name为空时返回入口文件（/sitemap.xml）
+// URL总数不超过单文件上限时入口文件直接列出所有URL，否则返回索引，子文件为pages-N.xml和articles-N.xml
+func (s *SitemapService) GetSitemap(name string) (*SitemapResult, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+
+	renderKey := fmt.Sprintf("%s:%s", global.CacheKeySitemapRender, name)
+	if cachedData, err := global.RedisDB.Get(sitemapCtxRedis, renderKey).Result(); err == nil {
+		var result SitemapResult
+		if err := json.Unmarshal([]byte(cachedData), &result); err == nil {
+			return &result, nil
+		}
+	} else if err != redis.Nil {
+		fmt.Printf("读取站点地图缓存失败: %v\n", err)
+	}
+
+	body, lastModified, err := s.render(name)
+	if err != nil {
+		return nil, err
+	}
+	result := &SitemapResult{
+		Body:         string(body),
+		ETag:         utils.ContentETag(body),
+		LastModified: lastModified,
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("序列化站点地图失败: %v", err)
+	}
+	if err := global.RedisDB.Set(sitemapCtxRedis, renderKey, resultJSON, time.Duration(global.CacheExpireArticles)*time.Second).Err(); err != nil {
+		fmt.Printf("写入站点地图缓存失败: %v\n", err)
+	}
+	return result, nil
+}
+
+// render 渲染指定的站点地图文件
+func (s *SitemapService) render(name string) ([]byte, time.Time, error) {
+	site := currentSiteConfig()
+
+	articleCount, err := global.RedisDB.ZCard(sitemapCtxRedis, global.CacheKeySitemapArticles).Result()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	pages, err := s.pageURLs(site)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if name == "" {
+		// 数量未超过上限时直接输出所有URL
+		if int(articleCount)+len(pages) <= utils.SitemapMaxURLs {
+			articles, err := s.articleURLs(site, 0, -1)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			urls := append(pages, articles...)
+			body, err := utils.RenderSitemap(urls)
+			return body, latestLastMod(urls), err
+		}
+
+		var sitemaps []utils.SitemapURL
+		for i := 1; i <= sitemapFileCount(int64(len(pages))); i++ {
+			start, stop := sitemapFileRange(i, int64(len(pages)))
+			sitemaps = append(sitemaps, utils.SitemapURL{
+				Loc:     site.BaseURL + "/sitemaps/" + fmt.Sprintf(sitemapPagesFile, i),
+				LastMod: latestLastMod(pages[start : stop+1]),
+			})
+		}
+		for i := 1; i <= sitemapFileCount(articleCount); i++ {
+			sitemaps = append(sitemaps, utils.SitemapURL{Loc: site.BaseURL + "/sitemaps/" + fmt.Sprintf(sitemapArticlesFile, i)})
+		}
+		body, err := utils.RenderSitemapIndex(sitemaps)
+		return body, time.Now(), err
+	}
+
+	if page, ok := parseSitemapFile(name, sitemapPagesFile); ok {
+		if page > sitemapFileCount(int64(len(pages))) {
+			return nil, time.Time{}, fmt.Errorf("未找到该站点地图")
+		}
+		start, stop := sitemapFileRange(page, int64(len(pages)))
+		urls := pages[start : stop+1]
+		body, err := utils.RenderSitemap(urls)
+		return body, latestLastMod(urls), err
+	}
+
+	page, ok := parseSitemapFile(name, sitemapArticlesFile)
+	if !ok || page > sitemapFileCount(articleCount) {
+		return nil, time.Time{}, fmt.Errorf("未找到该站点地图")
+	}
+	start, stop := sitemapFileRange(page, articleCount)
+	urls, err := s.articleURLs(site, start, stop)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	body, err := utils.RenderSitemap(urls)
+	return body, latestLastMod(urls), err
+}
+
+// parseSitemapFile 从子站点地图文件名中解析编号（从1开始）
+func parseSitemapFile(name, format string) (int, bool) {
+	var page int
+	if _, err := fmt.Sscanf(name, format, &page); err != nil || page < 1 || name != fmt.Sprintf(format, page) {
+		return 0, false
+	}
+	return page, true
+}
+
+// sitemapFileCount 按单文件上限拆分后的文件数量
+func sitemapFileCount(total int64) int {
+	return int((total + utils.SitemapMaxURLs - 1) / utils.SitemapMaxURLs)
+}
+
+// sitemapFileRange 第page个文件包含的条目范围[start, stop]
+func sitemapFileRange(page int, total int64) (int64, int64) {
+	start := int64(page-1) * utils.SitemapMaxURLs
+	stop := min(start+utils.SitemapMaxURLs, total) - 1
+	return start, stop
+}
+
+// pageURLs 首页、标签页和分类页，标签和分类数量较少，直接查询
+func (s *SitemapService) pageURLs(site config.SiteConfig) ([]utils.SitemapURL, error) {
+	urls := []utils.SitemapURL{{Loc: homeURL(site)}}
+
+	var tags []model.Tag
+	if err := global.DB.Select("name", "updated_at").Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		urls = append(urls, utils.SitemapURL{Loc: tagURL(site, tag.Name), LastMod: tag.UpdatedAt})
+	}
+
+	var categories []model.Category
+	if err := global.DB.Select("name", "updated_at").Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		urls = append(urls, utils.SitemapURL{Loc: categoryURL(site, category.Name), LastMod: category.UpdatedAt})
+	}
+	return urls, nil
+}
+
+// articleURLs 按文章ID顺序读取[start, stop]范围内的文章URL
+func (s *SitemapService) articleURLs(site config.SiteConfig, start, stop int64) ([]utils.SitemapURL, error) {
+	ids, err := global.RedisDB.ZRange(sitemapCtxRedis, global.CacheKeySitemapArticles, start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []utils.SitemapURL{}, nil
+	}
+
+	urls := make([]utils.SitemapURL, 0, len(ids))
+	// 分批读取，避免单个命令过大
+	const batch = 1000
+	for i := 0; i < len(ids); i += batch {
+		end := min(i+batch, len(ids))
+		values, err := global.RedisDB.HMGet(sitemapCtxRedis, global.CacheKeySitemapEntries, ids[i:end]...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for j, value := range values {
+			raw, ok := value.(string)
+			if !ok {
+				continue
+			}
+			var entry sitemapEntry
+			if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+				continue
+			}
+			id, _ := strconv.ParseUint(ids[i+j], 10, 32)
+			urls = append(urls, utils.SitemapURL{
+				Loc:     articleURL(site, entry.Slug, uint(id)),
+				LastMod: time.Unix(entry.LastMod, 0),
+			})
+		}
+	}
+	return urls, nil
+}
+
+// RefreshArticles 文章变更后增量更新站点地图：已发布的写入或更新，其余（草稿、下线、删除）移除
+func (s *SitemapService) RefreshArticles(ids ...uint) {
+	if len(ids) == 0 {
+		return
+	}
+	loaded, err := global.RedisDB.Exists(sitemapCtxRedis, global.CacheKeySitemapLoaded).Result()
+	if err != nil {
+		log.Printf("更新站点地图失败: %v", err)
+		return
+	}
+	if loaded == 0 {
+		// 尚未加载且没有正在进行的加载时不处理，之后加载会从MySQL读取最新数据
+		rebuilding, err := global.RedisDB.Exists(sitemapCtxRedis, global.CacheKeyLockSitemapRebuild).Result()
+		if err != nil || rebuilding == 0 {
+			return
+		}
+		// 正在加载时，本次变更可能晚于加载读取MySQL，记下ID等加载完成后再处理
+		members := make([]interface{}, 0, len(ids))
+		for _, id := range ids {
+			members = append(members, strconv.FormatUint(uint64(id), 10))
+		}
+		pipe := global.RedisDB.TxPipeline()
+		pipe.SAdd(sitemapCtxRedis, global.CacheKeySitemapPending, members...)
+		pipe.Expire(sitemapCtxRedis, global.CacheKeySitemapPending, sitemapLockTTL)
+		if _, err := pipe.Exec(sitemapCtxRedis); err != nil {
+			log.Printf("记录站点地图待更新文章失败: %v", err)
+			return
+		}
+		// 加载可能已在记录前完成并处理完待更新集合，再检查一次，已加载则直接更新
+		loaded, err = global.RedisDB.Exists(sitemapCtxRedis, global.CacheKeySitemapLoaded).Result()
+		if err != nil || loaded == 0 {
+			return
+		}
+	}
+	s.applyArticles(ids)
+}
+
+// applyArticles 从MySQL读取文章的最新状态并写入站点地图
+func (s *SitemapService) applyArticles(ids []uint) {
+	var articles []model.Article
+	if err := global.DB.Select("id", "slug", "updated_at").
+		Where("id IN ? AND status = ?", ids, global.ArticleStatusPublished).
+		Find(&articles).Error; err != nil {
+		log.Printf("更新站点地图失败: %v", err)
+		return
+	}
+
+	published := make(map[uint]bool, len(articles))
+	pipe := global.RedisDB.TxPipeline()
+	s.writeEntries(pipe, global.CacheKeySitemapArticles, global.CacheKeySitemapEntries, articles)
+	for _, a := range articles {
+		published[a.ID] = true
+	}
+	for _, id := range ids {
+		if published[id] {
+			continue
+		}
+		member := strconv.FormatUint(uint64(id), 10)
+		pipe.ZRem(sitemapCtxRedis, global.CacheKeySitemapArticles, member)
+		pipe.HDel(sitemapCtxRedis, global.CacheKeySitemapEntries, member)
+	}
+	if _, err := pipe.Exec(sitemapCtxRedis); err != nil {
+		log.Printf("更新站点地图失败: %v", err)
+		return
+	}
+	s.clearRenderCache()
+}
+
+// ensureLoaded Redis中没有站点地图数据时（首次启动或Redis被清空）从MySQL全量加载一次
+func (s *SitemapService) ensureLoaded() error {
+	loaded, err := global.RedisDB.Exists(sitemapCtxRedis, global.CacheKeySitemapLoaded).Result()
+	if err != nil {
+		return err
+	}
+	if loaded > 0 {
+		return nil
+	}
+
+	token, ok, err := utils.AcquireLock(sitemapCtxRedis, global.CacheKeyLockSitemapRebuild, sitemapLockTTL)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("站点地图正在生成，请稍后重试")
+	}
+	defer func() {
+		if err := utils.ReleaseLock(context.Background(), global.CacheKeyLockSitemapRebuild, token); err != nil {
+			log.Printf("释放站点地图锁失败: %v", err)
+		}
+	}()
+
+	// 获取锁后再次检查，可能其他实例刚完成加载
+	loaded, err = global.RedisDB.Exists(sitemapCtxRedis, global.CacheKeySitemapLoaded).Result()
+	if err != nil {
+		return err
+	}
+	if loaded > 0 {
+		return nil
+	}
+	return s.rebuild()
+}
+
+// rebuild 从MySQL全量加载已发布文章，先写入临时键再整体替换
+func (s *SitemapService) rebuild() error {
+	tmpArticles := global.CacheKeySitemapArticles + ":rebuild"
+	tmpEntries := global.CacheKeySitemapEntries + ":rebuild"
+	// 此前的变更都已写入MySQL，会在下面的全量读取中包含
+	if err := global.RedisDB.Del(sitemapCtxRedis, tmpArticles, tmpEntries, global.CacheKeySitemapPending).Err(); err != nil {
+		return err
+	}
+
+	total := 0
+	var articles []model.Article
+	err := global.DB.Select("id", "slug", "updated_at").
+		Where("status = ?", global.ArticleStatusPublished).
+		FindInBatches(&articles, 1000, func(tx *gorm.DB, batch int) error {
+			pipe := global.RedisDB.Pipeline()
+			s.writeEntries(pipe, tmpArticles, tmpEntries, articles)
+			if _, err := pipe.Exec(sitemapCtxRedis); err != nil {
+				return err
+			}
+			total += len(articles)
+			return nil
+		}).Error
+	if err != nil {
+		return fmt.Errorf("加载站点地图失败: %v", err)
+	}
+
+	pipe := global.RedisDB.TxPipeline()
+	if total > 0 {
+		pipe.Rename(sitemapCtxRedis, tmpArticles, global.CacheKeySitemapArticles)
+		pipe.Rename(sitemapCtxRedis, tmpEntries, global.CacheKeySitemapEntries)
+	} else {
+		pipe.Del(sitemapCtxRedis, global.CacheKeySitemapArticles, global.CacheKeySitemapEntries)
+	}
+	pipe.Set(sitemapCtxRedis, global.CacheKeySitemapLoaded, time.Now().Unix(), 0)
+	if _, err := pipe.Exec(sitemapCtxRedis); err != nil {
+		return fmt.Errorf("保存站点地图失败: %v", err)
+	}
+	s.applyPending()
+	s.clearRenderCache()
+
+	log.Printf("站点地图已加载%d篇文章", total)
+	return nil
+}
+
+// applyPending 处理加载期间记录的变更文章
+func (s *SitemapService) applyPending() {
+	pipe := global.RedisDB.TxPipeline()
+	membersCmd := pipe.SMembers(sitemapCtxRedis, global.CacheKeySitemapPending)
+	pipe.Del(sitemapCtxRedis, global.CacheKeySitemapPending)
+	if _, err := pipe.Exec(sitemapCtxRedis); err != nil {
+		log.Printf("读取站点地图待更新文章失败: %v", err)
+		return
+	}
+
+	ids := make([]uint, 0, len(membersCmd.Val()))
+	for _, member := range membersCmd.Val() {
+		if id, err := strconv.ParseUint(member, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) > 0 {
+		s.applyArticles(ids)
+	}
+}
+
+// writeEntries 写入文章条目
+func (s *SitemapService) writeEntries(pipe redis.Pipeliner, articlesKey, entriesKey string, articles []model.Article) {
+	for _, a := range articles {
+		member := strconv.FormatUint(uint64(a.ID), 10)
+		entry, _ := json.Marshal(sitemapEntry{Slug: a.Slug, LastMod: a.UpdatedAt.Unix()})
+		pipe.ZAdd(sitemapCtxRedis, articlesKey, &redis.Z{Score: float64(a.ID), Member: member})
+		pipe.HSet(sitemapCtxRedis, entriesKey, member, entry)
+	}
+}
+
+// clearRenderCache 清除站点地图渲染结果，标签、分类变更时也需要调用
+func (s *SitemapService) clearRenderCache() {
+	keys, err := global.RedisDB.Keys(sitemapCtxRedis, global.CacheKeySitemapRender+":*").Result()
+	if err != nil {
+		fmt.Printf("获取站点地图缓存键失败: %v\n", err)
+		return
+	}
+	if len(keys) > 0 {
+		if err := global.RedisDB.Del(sitemapCtxRedis, keys...).Err(); err != nil {
+			fmt.Printf("清除站点地图缓存失败: %v\n", err)
+		}
+	}
+}
+
+// latestLastMod 所有条目中最新的更新时间
+func latestLastMod(urls []utils.SitemapURL) time.Time {
+	var latest time.Time
+	for _, u := range urls {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
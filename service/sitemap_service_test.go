@@ -0,0 +1,95 @@
+package service
+
+import (
+	"go_test/global"
+	"go_test/utils"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestParseSitemapFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		format string
+		page   int
+		ok     bool
+	}{
+		{"文章文件", "articles-2.xml", sitemapArticlesFile, 2, true},
+		{"页面文件", "pages-1.xml", sitemapPagesFile, 1, true},
+		{"类型不符", "pages-1.xml", sitemapArticlesFile, 0, false},
+		{"编号从1开始", "articles-0.xml", sitemapArticlesFile, 0, false},
+		{"负数编号", "articles--1.xml", sitemapArticlesFile, 0, false},
+		{"前导零", "articles-01.xml", sitemapArticlesFile, 0, false},
+		{"多余后缀", "articles-1.xml.gz", sitemapArticlesFile, 0, false},
+		{"缺少编号", "articles-.xml", sitemapArticlesFile, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, ok := parseSitemapFile(tt.file, tt.format)
+			if page != tt.page || ok != tt.ok {
+				t.Errorf("parseSitemapFile(%q) = (%d, %v), want (%d, %v)", tt.file, page, ok, tt.page, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSitemapFileRange(t *testing.T) {
+	const perFile = utils.SitemapMaxURLs
+	tests := []struct {
+		name  string
+		page  int
+		total int64
+		count int
+		start int64
+		stop  int64
+	}{
+		{"不足一个文件", 1, 10, 1, 0, 9},
+		{"正好一个文件", 1, perFile, 1, 0, perFile - 1},
+		{"最后一个文件不满", 2, perFile + 1, 2, perFile, perFile},
+		{"中间的文件", 2, 3 * perFile, 3, perFile, 2*perFile - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if count := sitemapFileCount(tt.total); count != tt.count {
+				t.Errorf("sitemapFileCount(%d) = %d, want %d", tt.total, count, tt.count)
+			}
+			start, stop := sitemapFileRange(tt.page, tt.total)
+			if start != tt.start || stop != tt.stop {
+				t.Errorf("sitemapFileRange(%d, %d) = [%d, %d], want [%d, %d]", tt.page, tt.total, start, stop, tt.start, tt.stop)
+			}
+		})
+	}
+	if count := sitemapFileCount(0); count != 0 {
+		t.Errorf("sitemapFileCount(0) = %d, want 0", count)
+	}
+}
+
+// 全量加载期间的变更记入待更新集合，没有加载时直接忽略
+func TestSitemapRefreshDuringRebuild(t *testing.T) {
+	mr := miniredis.RunT(t)
+	global.RedisDB = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer global.RedisDB.Close()
+
+	sitemap := NewSitemapService()
+	sitemap.RefreshArticles(1, 2)
+	if mr.Exists(global.CacheKeySitemapPending) {
+		t.Fatal("pending set should not be created without a rebuild in progress")
+	}
+
+	mr.Set(global.CacheKeyLockSitemapRebuild, "token")
+	sitemap.RefreshArticles(1, 2)
+	sitemap.RefreshArticles(2, 3)
+	members, err := mr.Members(global.CacheKeySitemapPending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 3 {
+		t.Errorf("pending = %v, want [1 2 3]", members)
+	}
+	if ttl := mr.TTL(global.CacheKeySitemapPending); ttl <= 0 {
+		t.Errorf("pending ttl = %v, want > 0", ttl)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type TagService struct{}
+
+func NewTagService() *TagService {
+	return &TagService{}
+}
+
+// CreateTag 创建标签业务逻辑
+func (s *TagService) CreateTag(req dto.TagRequest) (*dto.TagVO, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("标签名称不能为空")
+	}
+
+	tag := model.Tag{Name: name}
+	if err := global.DB.Create(&tag).Error; err != nil {
+		return nil, fmt.Errorf("创建失败，标签可能已存在")
+	}
+
+	return &dto.TagVO{
+		ID:      tag.ID,
+		Name:    tag.Name,
+		Created: tag.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// UpdateTag 重命名标签业务逻辑
+func (s *TagService) UpdateTag(id uint, req dto.TagRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("标签名称不能为空")
+	}
+
+	var existing model.Tag
+	if err := global.DB.Where("name = ? AND id != ?", name, id).First(&existing).Error; err == nil {
+		return fmt.Errorf("标签名称已存在")
+	}
+
+	result := global.DB.Model(&model.Tag{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		return fmt.Errorf("更新标签失败: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("标签不存在")
+	}
+
+	// 文章VO中包含标签名称，需要清除文章缓存
+	NewArticleService().clearAllCache()
+	return nil
+}
+
+// DeleteTag 删除标签，同时解除与文章的关联
+func (s *TagService) DeleteTag(id uint) error {
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM article_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&model.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("标签不存在")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	NewArticleService().clearAllCache()
+	return nil
+}
+
+// GetTagsWithCount 获取所有标签及每个标签下已发布文章数量
+func (s *TagService) GetTagsWithCount() ([]dto.TagVO, error) {
+	var rows []struct {
+		model.Tag
+		ArticleCount int64
+	}
+	err := global.DB.Model(&model.Tag{}).
+		Select("tags.*, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.status = ? AND articles.deleted_at IS NULL", global.ArticleStatusPublished).
+		Group("tags.id").
+		Order("article_count DESC, tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	vos := make([]dto.TagVO, 0, len(rows))
+	for _, r := range rows {
+		vos = append(vos, dto.TagVO{
+			ID:           r.ID,
+			Name:         r.Name,
+			ArticleCount: r.ArticleCount,
+			Created:      r.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return vos, nil
+}
+
+// findOrCreateTags 在事务中按名称查找标签，不存在的自动创建
+func findOrCreateTags(tx *gorm.DB, names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		tag := model.Tag{Name: name}
+		if err := tx.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, fmt.Errorf("保存标签失败: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// refreshTokenRetention 过期的刷新令牌保留时长，保留期内仍可识别重放
+const refreshTokenRetention = 24 * time.Hour
+
+type TokenService struct{}
+
+func NewTokenService() *TokenService {
+	return &TokenService{}
+}
+
+// StartSession 用户登录时创建会话并签发访问令牌和刷新令牌
+func (s *TokenService) StartSession(user model.User, client dto.ClientInfo) (*dto.TokenResponse, error) {
+	sessionID, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("生成令牌失败")
+	}
+
+	var response *dto.TokenResponse
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		tokens, expiresAt, err := s.issueTokens(tx, user, sessionID)
+		if err != nil {
+			return err
+		}
+
+		session := model.UserSession{
+			UserID:     user.ID,
+			SessionID:  sessionID,
+			TokenID:    tokens.tokenID,
+			Device:     utils.DeviceLabel(client.UserAgent),
+			UserAgent:  truncateRunes(client.UserAgent, 500),
+			IP:         client.IP,
+			LastSeenAt: time.Now(),
+			ExpiresAt:  expiresAt,
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return fmt.Errorf("生成令牌失败")
+		}
+		response = &tokens.TokenResponse
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// issuedTokens 签发结果，tokenID用于关联会话
+type issuedTokens struct {
+	dto.TokenResponse
+	tokenID string
+}
+
+// issueTokens 在会话下签发一对新令牌，返回令牌和刷新令牌的过期时间
+func (s *TokenService) issueTokens(tx *gorm.DB, user model.User, sessionID string) (*issuedTokens, time.Time, error) {
+	access, err := utils.GenerateJWT(user.Username, user.Role, user.ID, sessionID)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("生成令牌失败")
+	}
+	refresh, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("生成令牌失败")
+	}
+
+	record := model.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        sessionID,
+		TokenHash:       utils.HashToken(refresh),
+		AccessJTI:       access.ID,
+		AccessExpiresAt: access.ExpiresAt,
+		ExpiresAt:       time.Now().Add(s.refreshTTL()),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return nil, time.Time{}, fmt.Errorf("生成令牌失败")
+	}
+
+	return &issuedTokens{
+		TokenResponse: dto.TokenResponse{
+			Token:        access.Token,
+			RefreshToken: refresh,
+			ExpiresIn:    int64(time.Until(access.ExpiresAt).Seconds()),
+		},
+		tokenID: access.ID,
+	}, record.ExpiresAt, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+// 已使用过的刷新令牌再次出现说明令牌已泄露，注销整个登录会话
+func (s *TokenService) Refresh(refreshToken string, client dto.ClientInfo) (*dto.TokenResponse, error) {
+	var record model.RefreshToken
+	if err := global.DB.Where("token_hash = ?", utils.HashToken(refreshToken)).First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("刷新令牌无效")
+		}
+		return nil, err
+	}
+
+	if record.RevokedAt != nil {
+		return nil, fmt.Errorf("刷新令牌已失效，请重新登录")
+	}
+	if record.UsedAt != nil {
+		s.revokeFamilyOnReuse(record)
+		return nil, fmt.Errorf("刷新令牌已被使用，请重新登录")
+	}
+	if time.Now().After(record.ExpiresAt) {
+		return nil, fmt.Errorf("刷新令牌已过期，请重新登录")
+	}
+
+	var user model.User
+	if err := global.DB.First(&user, record.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("用户不存在")
+		}
+		return nil, err
+	}
+	if user.Status != global.UserStatusActive {
+		if err := s.RevokeAllSessions(user.ID); err != nil {
+			log.Printf("注销用户%d的登录状态失败: %v", user.ID, err)
+		}
+		return nil, fmt.Errorf("用户账户已被禁用")
+	}
+	// 账户被要求启用两步验证但尚未启用时（如刚被设为管理员），必须重新登录完成绑定
+	if !user.TOTPEnabled && NewTwoFactorService().isRequired(user) {
+		return nil, fmt.Errorf("管理员账户必须启用两步验证，请重新登录")
+	}
+
+	var response *dto.TokenResponse
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		// 条件更新保证并发刷新时只有一个请求能使用该令牌
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", record.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("刷新令牌已被使用，请重新登录")
+		}
+
+		tokens, expiresAt, err := s.issueTokens(tx, user, record.FamilyID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserSession{}).Where("session_id = ?", record.FamilyID).Updates(map[string]interface{}{
+			"token_id":     tokens.tokenID,
+			"ip":           client.IP,
+			"last_seen_at": time.Now(),
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+			return fmt.Errorf("更新登录会话失败: %v", err)
+		}
+		response = &tokens.TokenResponse
+		return nil
+	})
+	if err != nil {
+		if err.Error() == "刷新令牌已被使用，请重新登录" {
+			s.revokeFamilyOnReuse(record)
+		}
+		return nil, err
+	}
+	return response, nil
+}
+
+// Logout 注销当前登录会话：当前访问令牌加入黑名单，会话下的刷新令牌全部失效
+func (s *TokenService) Logout(userID uint, sessionID, tokenID string, expiresAt time.Time) error {
+	if err := utils.DenyAccessToken(tokenID, expiresAt); err != nil {
+		return fmt.Errorf("注销失败: %v", err)
+	}
+	if sessionID == "" {
+		return nil
+	}
+	return s.RevokeSession(userID, sessionID)
+}
+
+// RevokeSession 注销用户的指定登录会话
+func (s *TokenService) RevokeSession(userID uint, sessionID string) error {
+	return s.revokeSessions(userID, sessionID)
+}
+
+// RevokeAllSessions 注销用户在所有设备上的登录状态
+func (s *TokenService) RevokeAllSessions(userID uint) error {
+	return s.revokeSessions(userID)
+}
+
+// revokeFamilyOnReuse 检测到刷新令牌重放时注销整个会话，只记录日志
+func (s *TokenService) revokeFamilyOnReuse(record model.RefreshToken) {
+	log.Printf("检测到用户%d的刷新令牌被重复使用，注销会话%s", record.UserID, record.FamilyID)
+	if err := s.RevokeSession(record.UserID, record.FamilyID); err != nil {
+		log.Printf("注销会话%s失败: %v", record.FamilyID, err)
+	}
+}
+
+// revokeSessions 结束用户的登录会话，sessionIDs为空时结束全部会话
+// 先在数据库中标记会话和刷新令牌已注销，再把会话加入Redis黑名单直到其中最后一个访问令牌过期
+func (s *TokenService) revokeSessions(userID uint, sessionIDs ...string) error {
+	now := time.Now()
+	scope := func(db *gorm.DB, column string) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if len(sessionIDs) > 0 {
+			db = db.Where(column+" IN ?", sessionIDs)
+		}
+		return db
+	}
+
+	var active []struct {
+		FamilyID  string
+		ExpiresAt time.Time
+	}
+	if err := scope(global.DB.Model(&model.RefreshToken{}), "family_id").
+		Select("family_id, MAX(access_expires_at) AS expires_at").
+		Where("access_expires_at > ?", now).
+		Group("family_id").
+		Scan(&active).Error; err != nil {
+		return fmt.Errorf("查询登录令牌失败: %v", err)
+	}
+
+	// 先在数据库中标记注销，Redis不可用时鉴权中间件会改查会话表，同样能拦截
+	err := global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := scope(tx.Model(&model.RefreshToken{}), "family_id").
+			Where("revoked_at IS NULL").
+			Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("注销刷新令牌失败: %v", err)
+		}
+		if err := scope(tx.Model(&model.UserSession{}), "session_id").
+			Where("revoked_at IS NULL").
+			Update("revoked_at", now).Error; err != nil {
+			return fmt.Errorf("结束登录会话失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 黑名单只用于加速拦截，写入失败只记录日志
+	for _, session := range active {
+		if err := utils.DenySession(session.FamilyID, session.ExpiresAt); err != nil {
+			log.Printf("会话%s加入黑名单失败: %v", session.FamilyID, err)
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredTokens 清理用户已过期的刷新令牌和会话记录
+func (s *TokenService) PurgeExpiredTokens(userID uint) {
+	before := time.Now().Add(-refreshTokenRetention)
+	if err := global.DB.Where("user_id = ? AND expires_at < ?", userID, before).
+		Delete(&model.RefreshToken{}).Error; err != nil {
+		log.Printf("清理用户%d过期的刷新令牌失败: %v", userID, err)
+	}
+	if err := global.DB.Where("user_id = ? AND expires_at < ?", userID, before).
+		Delete(&model.UserSession{}).Error; err != nil {
+		log.Printf("清理用户%d过期的登录会话失败: %v", userID, err)
+	}
+}
+
+// refreshTTL 刷新令牌有效期
+func (s *TokenService) refreshTTL() time.Duration {
+	if jwtConfig := config.GetJWTConfig(); jwtConfig != nil && jwtConfig.RefreshExpireHours > 0 {
+		return time.Duration(jwtConfig.RefreshExpireHours) * time.Hour
+	}
+	return 30 * 24 * time.Hour
+}
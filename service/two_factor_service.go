@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"go_test/global"
+	"go_test/model"
+	"go_test/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 登录挑战的用途
+const (
+	challengeVerify = "verify" // 已启用两步验证，输入验证码
+	challengeSetup  = "setup"  // 账户必须启用两步验证，先绑定再登录
+)
+
+const (
+	recoveryCodeCount    = 10 // 每次生成的恢复码数量
+	challengeMaxAttempts = 5  // 同一个挑战允许输错验证码的次数
+)
+
+var twoFactorCtx = context.Background()
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService struct{}
+
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{}
+}
+
+// Setup 生成待确认的TOTP密钥，确认前不生效，重复调用会覆盖上一次的密钥
+func (s *TwoFactorService) Setup(userID uint) (*dto.TwoFactorSetupVO, error) {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("已启用两步验证")
+	}
+	return s.newPendingSecret(user)
+}
+
+// Confirm 用验证器App生成的第一个验证码确认绑定，返回恢复码（只显示这一次）
+func (s *TwoFactorService) Confirm(userID uint, code string) ([]string, error) {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, fmt.Errorf("已启用两步验证")
+	}
+	return s.enable(user, code)
+}
+
+// Disable 关闭两步验证，需同时提供密码和验证码（或恢复码）
+// 密码或验证码错误计入登录失败次数，避免被用来暴力猜测
+func (s *TwoFactorService) Disable(userID uint, password, code, clientIP string) error {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return fmt.Errorf("未启用两步验证")
+	}
+	if s.isRequired(user) {
+		return fmt.Errorf("管理员账户必须启用两步验证")
+	}
+
+	attempt, err := NewLoginGuardService().Acquire(user.Username, clientIP)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPassword(password, user.Password) {
+		attempt.Fail()
+		return fmt.Errorf("密码错误")
+	}
+	if err := s.checkCode(user, code, attempt); err != nil {
+		return err
+	}
+
+	return global.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"totp_secret":    "",
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		}).Error; err != nil {
+			return fmt.Errorf("关闭两步验证失败: %v", err)
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error
+	})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部作废；验证码错误计入登录失败次数
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code, clientIP string) ([]string, error) {
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TOTPEnabled {
+		return nil, fmt.Errorf("未启用两步验证")
+	}
+
+	attempt, err := NewLoginGuardService().Acquire(user.Username, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCode(user, code, attempt); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// loginChallenge 密码验证通过后判断是否需要第二步，需要时返回只包含挑战令牌的登录响应
+func (s *TwoFactorService) loginChallenge(user model.User) (*dto.AuthResponse, error) {
+	purpose := ""
+	if user.TOTPEnabled {
+		purpose = challengeVerify
+	} else if s.isRequired(user) {
+		purpose = challengeSetup
+	} else {
+		return nil, nil
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("生成令牌失败")
+	}
+	key := s.challengeKey(token)
+	pipe := global.RedisDB.TxPipeline()
+	pipe.HSet(twoFactorCtx, key, "user_id", user.ID, "purpose", purpose, "attempts", 0)
+	pipe.Expire(twoFactorCtx, key, s.challengeTTL())
+	if _, err := pipe.Exec(twoFactorCtx); err != nil {
+		return nil, fmt.Errorf("生成登录验证失败: %v", err)
+	}
+
+	response := &dto.AuthResponse{
+		Username:          user.Username,
+		Role:              user.Role,
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		Message:           "请输入两步验证码",
+	}
+	if purpose == challengeSetup {
+		response.TwoFactorSetupRequired = true
+		response.Message = "管理员账户必须先绑定两步验证"
+	}
+	return response, nil
+}
+
+// SetupWithChallenge 登录过程中为必须启用两步验证的账户生成密钥
+func (s *TwoFactorService) SetupWithChallenge(challengeToken string) (*dto.TwoFactorSetupVO, error) {
+	userID, purpose, err := s.loadChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if purpose != challengeSetup {
+		return nil, fmt.Errorf("无需绑定两步验证")
+	}
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.newPendingSecret(user)
+}
+
+// VerifyChallenge 登录第二步：校验验证码（或恢复码）后创建会话并签发令牌
+// 登录过程中完成绑定的，响应中附带恢复码
+func (s *TwoFactorService) VerifyChallenge(challengeToken, code string, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	userID, purpose, err := s.loadChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Status != global.UserStatusActive {
+		return nil, fmt.Errorf("用户账户已被禁用")
+	}
+	attempt, err := NewLoginGuardService().Acquire(user.Username, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if purpose == challengeSetup {
+		if user.TOTPSecret == "" {
+			return nil, fmt.Errorf("请先生成两步验证密钥")
+		}
+		recoveryCodes, err = s.enable(user, code)
+		if err != nil && err.Error() != "验证码错误" {
+			attempt.Release()
+			return nil, err
+		}
+	} else {
+		var ok bool
+		if ok, err = s.verifyCode(user, code); err != nil {
+			attempt.Release()
+			return nil, err
+		}
+		if !ok {
+			err = fmt.Errorf("验证码错误")
+		}
+	}
+	if err != nil {
+		attempt.Fail()
+		return nil, s.failChallenge(challengeToken)
+	}
+	attempt.Release()
+	NewLoginGuardService().Reset(user.Username)
+
+	// 挑战只能使用一次，并发请求中只有删除成功的一方继续
+	deleted, err := global.RedisDB.Del(twoFactorCtx, s.challengeKey(challengeToken)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, fmt.Errorf("登录验证已过期，请重新登录")
+	}
+
+	tokens, err := NewTokenService().StartSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuthResponse{
+		Username:      user.Username,
+		Role:          user.Role,
+		Token:         tokens.Token,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresIn:     tokens.ExpiresIn,
+		RecoveryCodes: recoveryCodes,
+		Message:       "登录成功",
+	}, nil
+}
+
+// newPendingSecret 生成新密钥并加密保存为待确认状态
+func (s *TwoFactorService) newPendingSecret(user model.User) (*dto.TwoFactorSetupVO, error) {
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败")
+	}
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf("生成密钥失败")
+	}
+	if err := global.DB.Model(&model.User{}).Where("id = ? AND totp_enabled = ?", user.ID, false).
+		Update("totp_secret", encrypted).Error; err != nil {
+		return nil, fmt.Errorf("保存密钥失败: %v", err)
+	}
+
+	return &dto.TwoFactorSetupVO{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(s.issuer(), user.Username, secret),
+	}, nil
+}
+
+// enable 校验待确认密钥的验证码，启用两步验证并生成恢复码
+func (s *TwoFactorService) enable(user model.User, code string) ([]string, error) {
+	if user.TOTPSecret == "" {
+		return nil, fmt.Errorf("请先生成两步验证密钥")
+	}
+	secret, err := utils.DecryptSecret(user.TOTPSecret)
+	if err != nil {
+		return nil, fmt.Errorf("两步验证密钥无效，请重新生成")
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("验证码错误")
+	}
+
+	var codes []string
+	err = global.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ? AND totp_enabled = ?", user.ID, false).Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("启用两步验证失败: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("已启用两步验证")
+		}
+		codes, err = s.replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// checkCode 校验验证码并记录到登录保护：错误计为一次失败，否则退回预占的尝试
+func (s *TwoFactorService) checkCode(user model.User, code string, attempt *LoginAttempt) error {
+	ok, err := s.verifyCode(user, code)
+	if err != nil {
+		attempt.Release()
+		return err
+	}
+	if !ok {
+		attempt.Fail()
+		return fmt.Errorf("验证码错误")
+	}
+	attempt.Release()
+	return nil
+}
+
+// verifyCode 校验TOTP验证码或恢复码；TOTP同一时间步只能用一次，恢复码用后作废
+func (s *TwoFactorService) verifyCode(user model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		if _, err := strconv.Atoi(code); err == nil {
+			secret, err := utils.DecryptSecret(user.TOTPSecret)
+			if err != nil {
+				return false, fmt.Errorf("两步验证密钥无效，请联系管理员")
+			}
+			step, ok := utils.ValidateTOTP(secret, code, time.Now())
+			if !ok {
+				return false, nil
+			}
+			result := global.DB.Model(&model.User{}).Where("id = ? AND totp_last_step < ?", user.ID, step).
+				Update("totp_last_step", step)
+			if result.Error != nil {
+				return false, result.Error
+			}
+			return result.RowsAffected == 1, nil
+		}
+	}
+
+	result := global.DB.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashToken(normalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// replaceRecoveryCodes 删除旧恢复码并生成新的一组，返回明文
+func (s *TwoFactorService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return nil, fmt.Errorf("生成恢复码失败: %v", err)
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]model.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 8)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("生成恢复码失败")
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		records = append(records, model.RecoveryCode{UserID: userID, CodeHash: utils.HashToken(raw)})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, fmt.Errorf("生成恢复码失败: %v", err)
+	}
+	return codes, nil
+}
+
+// loadChallenge 读取登录挑战，返回用户ID和用途
+func (s *TwoFactorService) loadChallenge(token string) (uint, string, error) {
+	values, err := global.RedisDB.HGetAll(twoFactorCtx, s.challengeKey(token)).Result()
+	if err != nil {
+		return 0, "", err
+	}
+	userID, err := strconv.ParseUint(values["user_id"], 10, 32)
+	if err != nil || userID == 0 {
+		return 0, "", fmt.Errorf("登录验证已过期，请重新登录")
+	}
+	return uint(userID), values["purpose"], nil
+}
+
+// failChallengeScript 挑战仍存在时错误次数加一，达到上限时删除挑战；挑战已过期时返回-1，不会重新创建没有过期时间的键
+const failChallengeScript = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+if attempts >= tonumber(ARGV[1]) then
+	redis.call("DEL", KEYS[1])
+end
+return attempts
+`
+
+// failChallenge 记录一次验证码错误，超过次数后作废挑战
+func (s *TwoFactorService) failChallenge(token string) error {
+	attempts, err := global.RedisDB.Eval(twoFactorCtx, failChallengeScript, []string{s.challengeKey(token)}, challengeMaxAttempts).Int64()
+	if err != nil {
+		return err
+	}
+	if attempts < 0 {
+		return fmt.Errorf("登录验证已过期，请重新登录")
+	}
+	if attempts >= challengeMaxAttempts {
+		return fmt.Errorf("验证失败次数过多，请重新登录")
+	}
+	return fmt.Errorf("验证码错误")
+}
+
+func (s *TwoFactorService) loadUser(userID uint) (model.User, error) {
+	var user model.User
+	if err := global.DB.First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return user, fmt.Errorf("用户不存在")
+		}
+		return user, err
+	}
+	return user, nil
+}
+
+// isRequired 账户是否被要求必须启用两步验证
+func (s *TwoFactorService) isRequired(user model.User) bool {
+	cfg := config.GetTwoFactorConfig()
+	return cfg != nil && cfg.RequireForAdmin && user.Role == global.RoleAdmin
+}
+
+func (s *TwoFactorService) challengeKey(token string) string {
+	return fmt.Sprintf("%s:%s", global.CacheKeyTwoFactor, utils.HashToken(token))
+}
+
+func (s *TwoFactorService) challengeTTL() time.Duration {
+	if cfg := config.GetTwoFactorConfig(); cfg != nil && cfg.ChallengeMinutes > 0 {
+		return time.Duration(cfg.ChallengeMinutes) * time.Minute
+	}
+	return 5 * time.Minute
+}
+
+func (s *TwoFactorService) issuer() string {
+	if cfg := config.GetTwoFactorConfig(); cfg != nil && cfg.Issuer != "" {
+		return cfg.Issuer
+	}
+	if site := currentSiteConfig(); site.Title != "" {
+		return site.Title
+	}
+	return "GoBlog"
+}
+
+// normalizeRecoveryCode 恢复码不区分大小写，忽略分隔符和空白
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(code, func(r rune) bool {
+		return r == '-' || r == ' '
+	}), ""))
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"go_test/global"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestFailChallenge(t *testing.T) {
+	mr := miniredis.RunT(t)
+	global.RedisDB = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer global.RedisDB.Close()
+
+	s := NewTwoFactorService()
+	key := s.challengeKey("token")
+	mr.HSet(key, "user_id", "1")
+	mr.SetTTL(key, time.Minute)
+
+	for i := 1; i < challengeMaxAttempts; i++ {
+		if err := s.failChallenge("token"); err == nil || err.Error() != "验证码错误" {
+			t.Fatalf("attempt %d: error = %v, want 验证码错误", i, err)
+		}
+	}
+	// 过期时间保持不变
+	if ttl := mr.TTL(key); ttl != time.Minute {
+		t.Errorf("ttl = %v, want %v", ttl, time.Minute)
+	}
+	if err := s.failChallenge("token"); err == nil || err.Error() != "验证失败次数过多，请重新登录" {
+		t.Fatalf("error = %v, want 验证失败次数过多", err)
+	}
+	if mr.Exists(key) {
+		t.Error("challenge should be deleted after too many attempts")
+	}
+
+	// 挑战已过期时不会重新创建没有过期时间的键
+	if err := s.failChallenge("token"); err == nil || err.Error() != "登录验证已过期，请重新登录" {
+		t.Fatalf("error = %v, want 登录验证已过期", err)
+	}
+	if mr.Exists(key) {
+		t.Error("expired challenge should not be recreated")
+	}
+}
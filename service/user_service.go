@@ -6,6 +6,7 @@ import (
 	"go_test/global"
 	"go_test/model"
 	"go_test/utils"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -38,6 +39,9 @@ func (s *UserService) GetProfile(userID uint) (*dto.UserProfileVO, error) {
 		Status:   user.Status,
 		Created:  user.CreatedAt.Format("2006-01-02 15:04:05"),
 		Updated:  user.UpdatedAt.Format("2006-01-02 15:04:05"),
+
+		TwoFactorEnabled: user.TOTPEnabled,
+		EmailVerified:    user.EmailVerifiedAt != nil,
 	}, nil
 }
 
@@ -53,6 +57,10 @@ func (s *UserService) UpdateProfile(userID uint, req dto.UpdateProfileRequest) e
 			return fmt.Errorf("邮箱已被其他用户使用")
 		}
 		updateData["email"] = req.Email
+		// 更换邮箱后需要重新验证
+		if s.emailChanged(userID, req.Email) {
+			updateData["email_verified_at"] = nil
+		}
 	}
 
 	if req.Avatar != "" {
@@ -136,6 +144,9 @@ func (s *UserService) UpdateUserByAdmin(targetUserID uint, req dto.AdminUpdateUs
 			return fmt.Errorf("邮箱已被其他用户使用")
 		}
 		updateData["email"] = req.Email
+		if s.emailChanged(targetUserID, req.Email) {
+			updateData["email_verified_at"] = nil
+		}
 	}
 
 	if req.Avatar != "" {
@@ -154,6 +165,14 @@ func (s *UserService) UpdateUserByAdmin(targetUserID uint, req dto.AdminUpdateUs
 		updateData["phone"] = req.Phone
 	}
 
+	var target model.User
+	if err := global.DB.Select("id", "role").First(&target, targetUserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fmt.Errorf("用户不存在")
+		}
+		return err
+	}
+
 	// 管理员可以修改角色和状态
 	if req.Role != "" {
 		updateData["role"] = req.Role
@@ -173,6 +192,13 @@ func (s *UserService) UpdateUserByAdmin(targetUserID uint, req dto.AdminUpdateUs
 		return fmt.Errorf("更新用户资料失败: %v", err)
 	}
 
+	// 禁用账户或变更角色后，已签发的令牌不再可信，注销所有登录状态
+	if req.Status == global.UserStatusDisabled || (req.Role != "" && req.Role != target.Role) {
+		if err := NewTokenService().RevokeAllSessions(targetUserID); err != nil {
+			return fmt.Errorf("用户资料已更新，但注销登录状态失败，请重试: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -197,8 +223,20 @@ func (s *UserService) GetAllUsers() ([]dto.UserProfileVO, error) {
 			Status:   user.Status,
 			Created:  user.CreatedAt.Format("2006-01-02 15:04:05"),
 			Updated:  user.UpdatedAt.Format("2006-01-02 15:04:05"),
+
+			TwoFactorEnabled: user.TOTPEnabled,
+			EmailVerified:    user.EmailVerifiedAt != nil,
 		})
 	}
 
 	return vos, nil
 }
+
+// emailChanged 判断新邮箱与用户当前邮箱是否不同
+func (s *UserService) emailChanged(userID uint, email string) bool {
+	var user model.User
+	if err := global.DB.Select("id", "email").First(&user, userID).Error; err != nil {
+		return true
+	}
+	return !strings.EqualFold(user.Email, email)
+}
@@ -0,0 +1,193 @@
+package utils
+
+import "strings"
+
+// 差异行类型
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine 行级差异中的一行
+type DiffLine struct {
+	Type    string `json:"type"`               // equal/insert/delete
+	OldLine int    `json:"old_line,omitempty"` // 在旧文本中的行号（从1开始），新增行为0
+	NewLine int    `json:"new_line,omitempty"` // 在新文本中的行号（从1开始），删除行为0
+	Text    string `json:"text"`
+}
+
+// DiffMaxCells 逐行比较的规模上限：去掉首尾相同的行之后，新旧行数的乘积超过该值时整段视为删除后重新插入
+// 比较次数与乘积成正比，按乘积限制才能约束单次请求的耗时（如2000行对2000行）
+const DiffMaxCells = 4_000_000
+
+// DiffLines 基于最长公共子序列计算两段文本的行级差异
+// 先去掉首尾相同的行，中间部分用Hirschberg算法求LCS，内存占用与行数成线性关系
+func DiffLines(oldText, newText string) []DiffLine {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	oldMid := oldLines[prefix : len(oldLines)-suffix]
+	newMid := newLines[prefix : len(newLines)-suffix]
+
+	ops := make([]diffOp, 0, len(oldLines)+len(newLines))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{kind: DiffEqual, oldIdx: i, newIdx: i})
+	}
+	if len(oldMid)*len(newMid) > DiffMaxCells {
+		for i := range oldMid {
+			ops = append(ops, diffOp{kind: DiffDelete, oldIdx: prefix + i})
+		}
+		for j := range newMid {
+			ops = append(ops, diffOp{kind: DiffInsert, newIdx: prefix + j})
+		}
+	} else {
+		ops = hirschberg(oldMid, newMid, prefix, prefix, ops)
+	}
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, diffOp{kind: DiffEqual, oldIdx: len(oldLines) - suffix + k, newIdx: len(newLines) - suffix + k})
+	}
+
+	// 连续的修改块中先列出删除行，再列出新增行
+	result := make([]DiffLine, 0, len(ops))
+	for start := 0; start < len(ops); {
+		if ops[start].kind == DiffEqual {
+			op := ops[start]
+			result = append(result, DiffLine{Type: DiffEqual, OldLine: op.oldIdx + 1, NewLine: op.newIdx + 1, Text: oldLines[op.oldIdx]})
+			start++
+			continue
+		}
+		end := start
+		for end < len(ops) && ops[end].kind != DiffEqual {
+			end++
+		}
+		for _, op := range ops[start:end] {
+			if op.kind == DiffDelete {
+				result = append(result, DiffLine{Type: DiffDelete, OldLine: op.oldIdx + 1, Text: oldLines[op.oldIdx]})
+			}
+		}
+		for _, op := range ops[start:end] {
+			if op.kind == DiffInsert {
+				result = append(result, DiffLine{Type: DiffInsert, NewLine: op.newIdx + 1, Text: newLines[op.newIdx]})
+			}
+		}
+		start = end
+	}
+	return result
+}
+
+// diffOp 编辑操作，oldIdx/newIdx为从0开始的行下标
+type diffOp struct {
+	kind   string
+	oldIdx int
+	newIdx int
+}
+
+// hirschberg 分治求a与b的编辑序列，a、b在原文中的起始下标为aOff、bOff
+func hirschberg(a, b []string, aOff, bOff int, ops []diffOp) []diffOp {
+	switch {
+	case len(a) == 0:
+		for j := range b {
+			ops = append(ops, diffOp{kind: DiffInsert, newIdx: bOff + j})
+		}
+		return ops
+	case len(b) == 0:
+		for i := range a {
+			ops = append(ops, diffOp{kind: DiffDelete, oldIdx: aOff + i})
+		}
+		return ops
+	case len(a) == 1:
+		match := -1
+		for j := range b {
+			if b[j] == a[0] {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			ops = append(ops, diffOp{kind: DiffDelete, oldIdx: aOff})
+			for j := range b {
+				ops = append(ops, diffOp{kind: DiffInsert, newIdx: bOff + j})
+			}
+			return ops
+		}
+		for j := 0; j < match; j++ {
+			ops = append(ops, diffOp{kind: DiffInsert, newIdx: bOff + j})
+		}
+		ops = append(ops, diffOp{kind: DiffEqual, oldIdx: aOff, newIdx: bOff + match})
+		for j := match + 1; j < len(b); j++ {
+			ops = append(ops, diffOp{kind: DiffInsert, newIdx: bOff + j})
+		}
+		return ops
+	}
+
+	// 在a的中点把问题一分为二，找到使两侧LCS之和最大的b的切分点
+	mid := len(a) / 2
+	forward := lcsForward(a[:mid], b)
+	backward := lcsBackward(a[mid:], b)
+	split, best := 0, -1
+	for k := 0; k <= len(b); k++ {
+		if score := forward[k] + backward[k]; score > best {
+			split, best = k, score
+		}
+	}
+
+	ops = hirschberg(a[:mid], b[:split], aOff, bOff, ops)
+	return hirschberg(a[mid:], b[split:], aOff+mid, bOff+split, ops)
+}
+
+// lcsForward 返回row[k] = LCS(a, b[:k])，只保留一行
+func lcsForward(a, b []string) []int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for i := range a {
+		for k := 1; k <= len(b); k++ {
+			if a[i] == b[k-1] {
+				curr[k] = prev[k-1] + 1
+			} else if prev[k] >= curr[k-1] {
+				curr[k] = prev[k]
+			} else {
+				curr[k] = curr[k-1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev
+}
+
+// lcsBackward 返回row[k] = LCS(a, b[k:])，只保留一行
+func lcsBackward(a, b []string) []int {
+	n := len(b)
+	prev := make([]int, n+1)
+	curr := make([]int, n+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for k := n - 1; k >= 0; k-- {
+			if a[i] == b[k] {
+				curr[k] = prev[k+1] + 1
+			} else if prev[k] >= curr[k+1] {
+				curr[k] = prev[k]
+			} else {
+				curr[k] = curr[k+1]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev
+}
+
+// splitLines 按行切分文本，兼容\r\n换行，空文本返回空切片
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// formatDiff 把差异结果写成紧凑形式便于比较：" 行"、"-行"、"+行"
+func formatDiff(lines []DiffLine) []string {
+	out := make([]string, 0, len(lines))
+	for _, l := range lines {
+		switch l.Type {
+		case DiffEqual:
+			out = append(out, " "+l.Text)
+		case DiffDelete:
+			out = append(out, "-"+l.Text)
+		case DiffInsert:
+			out = append(out, "+"+l.Text)
+		}
+	}
+	return out
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{"都为空", "", "", []string{}},
+		{"全部新增", "", "a\nb", []string{"+a", "+b"}},
+		{"全部删除", "a\nb", "", []string{"-a", "-b"}},
+		{"完全相同", "a\nb", "a\nb", []string{" a", " b"}},
+		{"修改中间一行", "a\nb\nc", "a\nx\nc", []string{" a", "-b", "+x", " c"}},
+		{"插入和删除", "a\nb\nc\nd", "a\nc\nd\ne", []string{" a", "-b", " c", " d", "+e"}},
+		{"兼容CRLF", "a\r\nb", "a\nb", []string{" a", " b"}},
+		{"修改块中先删后增", "a\nb\nc\nd", "a\nx\ny\nd", []string{" a", "-b", "-c", "+x", "+y", " d"}},
+		{"保留最长公共子序列", "a\nb\nc\nd\ne\nf", "b\nx\nd\ne\ny", []string{"-a", " b", "-c", "+x", " d", " e", "-f", "+y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDiff(DiffLines(tt.old, tt.new)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLineNumbers(t *testing.T) {
+	got := DiffLines("a\nb\nc", "a\nx\nc")
+	want := []DiffLine{
+		{Type: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+		{Type: DiffDelete, OldLine: 2, Text: "b"},
+		{Type: DiffInsert, NewLine: 2, Text: "x"},
+		{Type: DiffEqual, OldLine: 3, NewLine: 3, Text: "c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffLines() = %+v, want %+v", got, want)
+	}
+}
+
+// 超过比较规模上限时不做逐行比较，中间部分整段删除后重新插入，首尾相同的行仍保留
+func TestDiffLinesMaxCells(t *testing.T) {
+	n := 2001
+	oldLines := make([]string, n)
+	newLines := make([]string, n)
+	for i := 0; i < n; i++ {
+		oldLines[i] = fmt.Sprintf("old %d", i)
+		newLines[i] = fmt.Sprintf("new %d", i)
+	}
+	oldText := "head\n" + strings.Join(oldLines, "\n") + "\ntail"
+	newText := "head\n" + strings.Join(newLines, "\n") + "\ntail"
+
+	got := DiffLines(oldText, newText)
+	if len(got) != 2*n+2 {
+		t.Fatalf("len = %d, want %d", len(got), 2*n+2)
+	}
+	if got[0].Type != DiffEqual || got[len(got)-1].Type != DiffEqual {
+		t.Errorf("head/tail should be equal, got %q and %q", got[0].Type, got[len(got)-1].Type)
+	}
+	for i, line := range got[1 : len(got)-1] {
+		want := DiffDelete
+		if i >= n {
+			want = DiffInsert
+		}
+		if line.Type != want {
+			t.Fatalf("line %d type = %q, want %q", i+1, line.Type, want)
+		}
+	}
+}
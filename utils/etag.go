@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VersionETag 根据版本号生成强校验ETag，如 "3"
+func VersionETag(version uint) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// ParseVersionETag 从If-Match请求头中解析版本号，格式错误或为弱校验ETag时返回false
+func ParseVersionETag(header string) (uint, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || strings.HasPrefix(header, "W/") {
+		return 0, false
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(header, "\""), 10, 32)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// ContentETag 根据内容生成强校验ETag
+func ContentETag(body []byte) string {
+	return fmt.Sprintf("\"%x\"", md5.Sum(body))
+}
+
+// IsNotModified 条件请求校验：If-None-Match优先，其次If-Modified-Since
+func IsNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		// HTTP日期只精确到秒
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	published := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	return Feed{
+		Title:       "博客",
+		Description: "最新文章",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/feed.xml",
+		Language:    "zh-CN",
+		Author:      "站长",
+		Updated:     published.Add(time.Hour),
+		Items: []FeedItem{{
+			ID:          "https://example.com/articles/hello",
+			Title:       "Hello & <World>",
+			Link:        "https://example.com/articles/hello",
+			Summary:     "摘要",
+			ContentHTML: "<p>正文]]>结束</p>",
+			Author:      "alice",
+			Tags:        []string{"go", "web"},
+			Published:   published,
+			Updated:     published.Add(time.Hour),
+		}},
+	}
+}
+
+func TestRenderFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		render   func(Feed) ([]byte, error)
+		contains []string
+	}{
+		{
+			name:   "RSS",
+			render: RenderRSS,
+			contains: []string{
+				`<rss version="2.0"`,
+				`<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml">`,
+				"<title>Hello &amp; &lt;World&gt;</title>",
+				`<guid isPermaLink="true">https://example.com/articles/hello</guid>`,
+				"<category>go</category>",
+				"<pubDate>Wed, 01 May 2024 08:00:00 +0000</pubDate>",
+				"<lastBuildDate>Wed, 01 May 2024 09:00:00 +0000</lastBuildDate>",
+			},
+		},
+		{
+			name:   "Atom",
+			render: RenderAtom,
+			contains: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom">`,
+				"<id>https://example.com/feed.xml</id>",
+				"<updated>2024-05-01T09:00:00Z</updated>",
+				`<link href="https://example.com/feed.xml" rel="self" type="application/atom+xml">`,
+				"<published>2024-05-01T08:00:00Z</published>",
+				"<author>\n      <name>alice</name>",
+				`<category term="web">`,
+				`<content type="html">&lt;p&gt;正文]]&gt;结束&lt;/p&gt;</content>`,
+			},
+		},
+		{
+			name:   "JSON Feed",
+			render: RenderJSONFeed,
+			contains: []string{
+				`"version": "https://jsonfeed.org/version/1.1"`,
+				`"feed_url": "https://example.com/feed.xml"`,
+				`"date_published": "2024-05-01T08:00:00Z"`,
+				`"tags": [`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.render(testFeed())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(string(body), s) {
+					t.Errorf("output missing %q:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
+
+// 正文中出现CDATA结束符时仍能正确解析出原文
+func TestRenderRSSContentRoundTrip(t *testing.T) {
+	body, err := RenderRSS(testFeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Items []struct {
+			Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		} `xml:"channel>item"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Items) != 1 || doc.Items[0].Content != "<p>正文]]>结束</p>" {
+		t.Errorf("content = %+v", doc.Items)
+	}
+}
+
+func TestRenderJSONFeedEmpty(t *testing.T) {
+	body, err := RenderJSONFeed(Feed{Title: "空"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+	// 没有条目时items为空数组而不是null
+	if items, ok := doc["items"].([]interface{}); !ok || len(items) != 0 {
+		t.Errorf("items = %v, want []", doc["items"])
+	}
+	if _, ok := doc["authors"]; ok {
+		t.Error("authors should be omitted")
+	}
+}
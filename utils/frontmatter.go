@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// SplitFrontMatter 拆分Markdown文件开头的YAML front matter和正文
+// 正文保持原样，不去除首尾空白，保证导入导出往返无损
+func SplitFrontMatter(src []byte) ([]byte, []byte, error) {
+	// 去掉UTF-8 BOM
+	src = bytes.TrimPrefix(src, []byte("\xef\xbb\xbf"))
+	if bytes.HasPrefix(src, []byte("+++")) {
+		return nil, nil, fmt.Errorf("不支持TOML格式的front matter")
+	}
+
+	firstLine, rest, ok := cutLine(src)
+	if !ok || strings.TrimRight(string(firstLine), "\r") != frontMatterDelimiter {
+		return nil, nil, fmt.Errorf("缺少front matter")
+	}
+
+	offset := 0
+	for {
+		line, next, ok := cutLine(rest[offset:])
+		if strings.TrimRight(string(line), "\r") == frontMatterDelimiter {
+			return rest[:offset], next, nil
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("front matter缺少结束标记")
+		}
+		offset = len(rest) - len(next)
+	}
+}
+
+// JoinFrontMatter 将front matter和正文拼接为Markdown文件
+func JoinFrontMatter(frontMatter, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(frontMatter)
+	if len(frontMatter) > 0 && frontMatter[len(frontMatter)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// cutLine 切出第一行（不含换行符），ok表示是否找到换行符
+func cutLine(src []byte) ([]byte, []byte, bool) {
+	i := bytes.IndexByte(src, '\n')
+	if i < 0 {
+		return src, nil, false
+	}
+	return src[:i], src[i+1:], true
+}
+
+// frontMatterTimeLayouts Hexo、Hugo常见的日期格式，不带时区时按本地时间解析
+var frontMatterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FrontMatterTime front matter中的时间，兼容多种日期格式，导出为RFC3339（保留小数秒）
+type FrontMatterTime struct {
+	time.Time
+}
+
+func (t *FrontMatterTime) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimSpace(node.Value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range frontMatterTimeLayouts {
+		parsed, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("无法解析日期: %s", value)
+}
+
+func (t FrontMatterTime) MarshalYAML() (interface{}, error) {
+	return t.Format(time.RFC3339Nano), nil
+}
+
+// StringList front matter中的字符串列表，兼容单个字符串和嵌套列表（如Hexo的多级分类）
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(node *yaml.Node) error {
+	var values []string
+	var collect func(n *yaml.Node) error
+	collect = func(n *yaml.Node) error {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			if value := strings.TrimSpace(n.Value); value != "" {
+				values = append(values, value)
+			}
+		case yaml.SequenceNode:
+			for _, child := range n.Content {
+				if err := collect(child); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("第%d行：应为字符串或列表", n.Line)
+		}
+		return nil
+	}
+	if err := collect(node); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
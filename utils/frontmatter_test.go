@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name        string
+		src         string
+		frontMatter string
+		body        string
+		wantErr     string
+	}{
+		{"基本格式", "---\ntitle: a\n---\n正文\n", "title: a\n", "正文\n", ""},
+		{"正文保留首尾空白", "---\ntitle: a\n---\n\n  正文  \n\n", "title: a\n", "\n  正文  \n\n", ""},
+		{"兼容CRLF", "---\r\ntitle: a\r\n---\r\nbody", "title: a\r\n", "body", ""},
+		{"去掉BOM", "\xef\xbb\xbf---\ntitle: a\n---\n", "title: a\n", "", ""},
+		{"空front matter", "---\n---\nbody", "", "body", ""},
+		{"结束标记后没有换行", "---\ntitle: a\n---", "title: a\n", "", ""},
+		{"正文中的分隔线不影响", "---\ntitle: a\n---\n---\nbody", "title: a\n", "---\nbody", ""},
+		{"缺少front matter", "# 标题", "", "", "缺少front matter"},
+		{"缺少结束标记", "---\ntitle: a\n", "", "", "缺少结束标记"},
+		{"TOML格式", "+++\ntitle = 'a'\n+++\n", "", "", "不支持TOML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body, err := SplitFrontMatter([]byte(tt.src))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(frontMatter) != tt.frontMatter || string(body) != tt.body {
+				t.Errorf("SplitFrontMatter() = (%q, %q), want (%q, %q)", frontMatter, body, tt.frontMatter, tt.body)
+			}
+		})
+	}
+}
+
+// 拼接后再拆分得到原来的内容
+func TestFrontMatterRoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontMatter string
+		body        string
+	}{
+		{"普通正文", "title: a\n", "# 标题\n\n正文\n"},
+		{"正文以分隔线开头", "title: a\n", "---\n正文"},
+		{"front matter没有结尾换行", "title: a", "正文"},
+		{"空正文", "title: a\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frontMatter, body, err := SplitFrontMatter(JoinFrontMatter([]byte(tt.frontMatter), []byte(tt.body)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSuffix(string(frontMatter), "\n") != strings.TrimSuffix(tt.frontMatter, "\n") || string(body) != tt.body {
+				t.Errorf("round trip = (%q, %q), want (%q, %q)", frontMatter, body, tt.frontMatter, tt.body)
+			}
+		})
+	}
+}
+
+func TestFrontMatterTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"RFC3339", "2024-05-01T08:00:00+08:00", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"保留小数秒", "2024-05-01T08:00:00.123456789Z", time.Date(2024, 5, 1, 8, 0, 0, 123456789, time.UTC), false},
+		{"Hexo格式按本地时间", "2024-05-01 08:00:00", time.Date(2024, 5, 1, 8, 0, 0, 0, time.Local), false},
+		{"只有分钟", "2024-05-01 08:00", time.Date(2024, 5, 1, 8, 0, 0, 0, time.Local), false},
+		{"只有日期", "2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), false},
+		{"空值", `""`, time.Time{}, false},
+		{"无法解析", "yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FrontMatterTime
+			err := yaml.Unmarshal([]byte(tt.value), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("time = %v, want %v", got.Time, tt.want)
+			}
+		})
+	}
+}
+
+// 导出的时间再导入后不丢失精度
+func TestFrontMatterTimeRoundTrip(t *testing.T) {
+	type doc struct {
+		Date    FrontMatterTime `yaml:"date,omitempty"`
+		Updated FrontMatterTime `yaml:"updated,omitempty"`
+	}
+	in := doc{Date: FrontMatterTime{time.Date(2024, 5, 1, 8, 0, 0, 123000000, time.FixedZone("CST", 8*3600))}}
+	raw, err := yaml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "updated") {
+		t.Errorf("zero time should be omitted: %s", raw)
+	}
+	var out doc
+	if err := yaml.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Date.Equal(in.Date.Time) {
+		t.Errorf("date = %v, want %v", out.Date.Time, in.Date.Time)
+	}
+}
+
+func TestStringList(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    StringList
+		wantErr bool
+	}{
+		{"单个字符串", "Go", StringList{"Go"}, false},
+		{"列表", "[Go, Web]", StringList{"Go", "Web"}, false},
+		{"嵌套列表展开", "[[后端, Go], 数据库]", StringList{"后端", "Go", "数据库"}, false},
+		{"忽略空值", `[" ", Go]`, StringList{"Go"}, false},
+		{"不支持映射", "{a: b}", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got StringList
+			err := yaml.Unmarshal([]byte(tt.value), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StringList = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// HighlightMarker 高亮标记，EscapeHTML为true时会对标记以外的文本做HTML转义
+type HighlightMarker struct {
+	Pre        string
+	Post       string
+	EscapeHTML bool
+}
+
+var (
+	HighlightMarkHTML = HighlightMarker{Pre: "<mark>", Post: "</mark>", EscapeHTML: true}
+	HighlightMarkdown = HighlightMarker{Pre: "**", Post: "**", EscapeHTML: false}
+)
+
+// textRange 文本中的一段区间，按rune下标计算，左闭右开
+type textRange struct {
+	start int
+	end   int
+}
+
+// HighlightTerms 高亮文本中所有出现的搜索词（不区分大小写），返回高亮后的文本和是否命中
+// 多个词组成的短语之间可以是任意空白
+func HighlightTerms(text string, terms []string, marker HighlightMarker) (string, bool) {
+	runes := []rune(text)
+	matches := findTermMatches(runes, terms)
+	return renderHighlight(runes, matches, marker), len(matches) > 0
+}
+
+// MatchTerms 判断文本中是否出现任意一个搜索词
+func MatchTerms(text string, terms []string) bool {
+	return len(findTermMatches([]rune(text), terms)) > 0
+}
+
+// BuildSnippet 截取搜索词附近的上下文并高亮，radius为命中位置前后保留的字符数，最多返回maxFragments段
+// 文本中的连续空白会合并为一个空格；没有命中时返回开头的一段
+func BuildSnippet(text string, terms []string, radius, maxFragments int, marker HighlightMarker) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	matches := findTermMatches(runes, terms)
+	if len(matches) == 0 {
+		end := min(len(runes), radius*2)
+		snippet := renderHighlight(runes[:end], nil, marker)
+		if end < len(runes) {
+			snippet += "…"
+		}
+		return snippet
+	}
+
+	// 命中位置前后扩展出窗口，重叠的窗口合并
+	windows := make([]textRange, 0, len(matches))
+	for _, m := range matches {
+		w := textRange{start: max(0, m.start-radius), end: min(len(runes), m.end+radius)}
+		if n := len(windows); n > 0 && w.start <= windows[n-1].end {
+			windows[n-1].end = max(windows[n-1].end, w.end)
+			continue
+		}
+		windows = append(windows, w)
+	}
+	if maxFragments > 0 && len(windows) > maxFragments {
+		windows = windows[:maxFragments]
+	}
+
+	fragments := make([]string, 0, len(windows))
+	for _, w := range windows {
+		inside := make([]textRange, 0)
+		for _, m := range matches {
+			if m.start >= w.start && m.end <= w.end {
+				inside = append(inside, textRange{start: m.start - w.start, end: m.end - w.start})
+			}
+		}
+		fragment := renderHighlight(runes[w.start:w.end], inside, marker)
+		if w.start > 0 {
+			fragment = "…" + fragment
+		}
+		if w.end < len(runes) {
+			fragment += "…"
+		}
+		fragments = append(fragments, fragment)
+	}
+	return strings.Join(fragments, " ")
+}
+
+// findTermMatches 查找所有搜索词的出现位置，返回按位置排序并合并重叠后的区间
+func findTermMatches(runes []rune, terms []string) []textRange {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matches := make([]textRange, 0)
+	for _, term := range terms {
+		words := make([][]rune, 0)
+		for _, field := range strings.Fields(term) {
+			words = append(words, []rune(strings.ToLower(field)))
+		}
+		if len(words) == 0 {
+			continue
+		}
+		for i := 0; i < len(lower); i++ {
+			if end, ok := matchWords(lower, i, words); ok {
+				matches = append(matches, textRange{start: i, end: end})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+	merged := matches[:1]
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.start <= last.end {
+			last.end = max(last.end, m.end)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+// matchWords 判断从pos开始是否依次出现各个词（词之间至少一个空白），返回结束位置
+func matchWords(lower []rune, pos int, words [][]rune) (int, bool) {
+	for i, word := range words {
+		if i > 0 {
+			start := pos
+			for pos < len(lower) && unicode.IsSpace(lower[pos]) {
+				pos++
+			}
+			if pos == start {
+				return 0, false
+			}
+		}
+		if pos+len(word) > len(lower) {
+			return 0, false
+		}
+		for j, r := range word {
+			if lower[pos+j] != r {
+				return 0, false
+			}
+		}
+		pos += len(word)
+	}
+	return pos, true
+}
+
+// renderHighlight 在命中区间两侧插入高亮标记
+func renderHighlight(runes []rune, matches []textRange, marker HighlightMarker) string {
+	escape := func(rs []rune) string {
+		if marker.EscapeHTML {
+			return html.EscapeString(string(rs))
+		}
+		return string(rs)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		b.WriteString(escape(runes[last:m.start]))
+		b.WriteString(marker.Pre)
+		b.WriteString(escape(runes[m.start:m.end]))
+		b.WriteString(marker.Post)
+		last = m.end
+	}
+	b.WriteString(escape(runes[last:]))
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package utils
+
+import "testing"
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		terms   []string
+		marker  HighlightMarker
+		want    string
+		matched bool
+	}{
+		{"不区分大小写", "Go and GO", []string{"go"}, HighlightMarkHTML, "<mark>Go</mark> and <mark>GO</mark>", true},
+		{"转义标记以外的HTML", "<b>Go</b>", []string{"go"}, HighlightMarkHTML, "&lt;b&gt;<mark>Go</mark>&lt;/b&gt;", true},
+		{"Markdown不转义", "<b>Go</b>", []string{"go"}, HighlightMarkdown, "<b>**Go**</b>", true},
+		{"重叠的命中合并", "数据库", []string{"数据", "据库"}, HighlightMarkHTML, "<mark>数据库</mark>", true},
+		{"短语之间允许任意空白", "full \n text search", []string{"full text"}, HighlightMarkHTML, "<mark>full \n text</mark> search", true},
+		{"短语之间必须有空白", "fulltext", []string{"full text"}, HighlightMarkHTML, "fulltext", false},
+		{"没有命中", "hello", []string{"go"}, HighlightMarkHTML, "hello", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := HighlightTerms(tt.text, tt.terms, tt.marker)
+			if got != tt.want || matched != tt.matched {
+				t.Errorf("HighlightTerms(%q) = (%q, %v), want (%q, %v)", tt.text, got, matched, tt.want, tt.matched)
+			}
+		})
+	}
+}
+
+func TestBuildSnippet(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		terms        []string
+		radius       int
+		maxFragments int
+		want         string
+	}{
+		{"截取命中附近的上下文", "0123456789 go 0123456789", []string{"go"}, 3, 3, "…89 <mark>go</mark> 01…"},
+		{"合并连续空白", "a\n\n  go   b", []string{"go"}, 5, 3, "a <mark>go</mark> b"},
+		{"重叠的窗口合并为一段", "go 12 go 3456789", []string{"go"}, 3, 3, "<mark>go</mark> 12 <mark>go</mark> 34…"},
+		{"限制片段数量", "go 123456 go 123456 go", []string{"go"}, 2, 2, "<mark>go</mark> 1… …6 <mark>go</mark> 1…"},
+		{"没有命中时返回开头", "abcdefghij", []string{"go"}, 3, 3, "abcdef…"},
+		{"没有命中的短文本", "<p>", []string{"go"}, 3, 3, "&lt;p&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildSnippet(tt.text, tt.terms, tt.radius, tt.maxFragments, HighlightMarkHTML)
+			if got != tt.want {
+				t.Errorf("BuildSnippet(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchTerms(t *testing.T) {
+	if !MatchTerms("Hello World", []string{"nothing", "WORLD"}) {
+		t.Error("MatchTerms should match any term")
+	}
+	if MatchTerms("Hello World", []string{"go"}) {
+		t.Error("MatchTerms should not match")
+	}
+}
@@ -10,26 +10,65 @@ import (
 
 // UserClaims JWT用户信息结构
 type UserClaims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	UserID   uint   `json:"user_id"`
+	Username  string
+	Role      string
+	UserID    uint
+	ID        string    // 令牌ID（jti），用于注销后加入黑名单
+	SessionID string    // 登录会话ID，与刷新令牌的FamilyID对应
+	ExpiresAt time.Time // 过期时间
 }
 
-// GenerateJWT 生成JWT令牌（包含用户ID）
-func GenerateJWT(username, role string, userID uint) (string, error) {
+// AccessToken 签发的访问令牌
+type AccessToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
+
+// AccessTokenTTL 访问令牌有效期，未配置access_expire_minutes时兼容旧的expire_hours
+func AccessTokenTTL() time.Duration {
+	jwtConfig := config.GetJWTConfig()
+	if jwtConfig == nil {
+		return 15 * time.Minute
+	}
+	if jwtConfig.AccessExpireMinutes > 0 {
+		return time.Duration(jwtConfig.AccessExpireMinutes) * time.Minute
+	}
+	if jwtConfig.ExpireHours > 0 {
+		return time.Duration(jwtConfig.ExpireHours) * time.Hour
+	}
+	return 15 * time.Minute
+}
+
+// GenerateJWT 生成短期访问令牌（包含用户ID、令牌ID和会话ID）
+func GenerateJWT(username, role string, userID uint, sessionID string) (*AccessToken, error) {
 	jwtConfig := config.GetJWTConfig()
 	if jwtConfig == nil {
-		return "", errors.New("JWT配置未初始化")
+		return nil, errors.New("JWT配置未初始化")
 	}
 
+	jti, err := RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL())
 	claims := jwt.MapClaims{
 		"username": username,
 		"role":     role,
 		"user_id":  userID,
-		"exp":      time.Now().Add(time.Duration(jwtConfig.ExpireHours) * time.Hour).Unix(),
+		"jti":      jti,
+		"sid":      sessionID,
+		"iat":      now.Unix(),
+		"exp":      expiresAt.Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jwtConfig.Secret))
+	signed, err := token.SignedString([]byte(jwtConfig.Secret))
+	if err != nil {
+		return nil, err
+	}
+	return &AccessToken{Token: signed, ID: jti, ExpiresAt: expiresAt}, nil
 }
 
 // ParseJWT 校验并解析JWT令牌，返回用户信息和错误信息
@@ -58,10 +97,21 @@ func ParseJWT(tokenString string) (*UserClaims, error) {
 			return nil, errors.New("token中缺少必要的用户信息")
 		}
 
+		// 旧版本签发的令牌没有jti和sid，由中间件拒绝
+		jti, _ := claims["jti"].(string)
+		sid, _ := claims["sid"].(string)
+		var expiresAt time.Time
+		if exp, ok := claims["exp"].(float64); ok {
+			expiresAt = time.Unix(int64(exp), 0)
+		}
+
 		return &UserClaims{
-			Username: username,
-			Role:     role,
-			UserID:   uint(userIDFloat),
+			Username:  username,
+			Role:      role,
+			UserID:    uint(userIDFloat),
+			ID:        jti,
+			SessionID: sid,
+			ExpiresAt: expiresAt,
 		}, nil
 	}
 	return nil, errors.New("无效的token")
@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Mail 一封纯文本邮件
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口，生产环境使用SMTP，本地开发可只打印或写入文件
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+// SMTPMailer 通过SMTP服务器发送邮件；ImplicitTLS为true时直接建立TLS连接（465端口），否则在服务器支持时使用STARTTLS
+type SMTPMailer struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	From        string
+	ImplicitTLS bool
+}
+
+// smtpTimeout 连接和整个发送过程的超时时间，避免SMTP服务器无响应时发送协程一直挂起
+const smtpTimeout = 30 * time.Second
+
+func (m *SMTPMailer) Send(mail Mail) error {
+	addr := net.JoinHostPort(m.Host, fmt.Sprintf("%d", m.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if m.ImplicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: m.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !m.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(envelopeAddress(m.From)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(mail.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMailMessage(m.From, mail)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// mailTokenPattern 邮件正文中链接携带的令牌
+var mailTokenPattern = regexp.MustCompile(`token=[^\s&]+`)
+
+// LogMailer 本地开发用：把邮件打印到日志（链接中的令牌会隐藏），Dir不为空时同时写入完整的 .eml 文件
+type LogMailer struct {
+	From string
+	Dir  string
+}
+
+func (m *LogMailer) Send(mail Mail) error {
+	log.Printf("[mail] to=%s subject=%s\n%s", mail.To, mail.Subject, mailTokenPattern.ReplaceAllString(mail.Body, "token=[已隐藏]"))
+	if m.Dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), sanitizeFileName(mail.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), buildMailMessage(m.From, mail), 0o644)
+}
+
+// buildMailMessage 组装邮件头和正文，主题按RFC 2047编码以支持中文
+func buildMailMessage(from string, mail Mail) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + mail.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", mail.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// envelopeAddress 从 "名称 <地址>" 形式的发件人中取出SMTP信封使用的地址
+func envelopeAddress(from string) string {
+	if addr, err := netmail.ParseAddress(from); err == nil {
+		return addr.Address
+	}
+	return from
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+}
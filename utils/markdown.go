@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"go_test/config"
+	"go_test/dto"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+var (
+	markdownOnce     sync.Once
+	markdownRenderer goldmark.Markdown
+	sanitizePolicy   *bluemonday.Policy
+
+	// 代码块语言类名，如 language-go，供前端语法高亮使用
+	codeClassPattern = regexp.MustCompile(`^language-[a-zA-Z0-9_+#-]+$`)
+	// 标题锚点ID
+	headingIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// defaultAllowedTags 未配置白名单时使用的默认标签
+var defaultAllowedTags = []string{
+	"p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6", "strong", "em", "del",
+	"blockquote", "ul", "ol", "li", "a", "img", "code", "pre",
+	"table", "thead", "tbody", "tr", "th", "td",
+}
+
+// defaultAllowedAttrs 未配置白名单时使用的默认属性
+var defaultAllowedAttrs = map[string][]string{
+	"a":   {"href", "title"},
+	"img": {"src", "alt", "title"},
+	"th":  {"align"},
+	"td":  {"align"},
+}
+
+// initMarkdown 初始化Markdown渲染器和HTML白名单策略
+func initMarkdown() {
+	markdownRenderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		// 允许原始HTML，最终输出统一经过白名单过滤
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	policy := bluemonday.NewPolicy()
+	tags := defaultAllowedTags
+	schemes := []string{"http", "https", "mailto"}
+	attrs := defaultAllowedAttrs
+
+	if markdownConfig := config.GetMarkdownConfig(); markdownConfig != nil {
+		if len(markdownConfig.AllowedTags) > 0 {
+			tags = markdownConfig.AllowedTags
+		}
+		if len(markdownConfig.AllowedURLSchemes) > 0 {
+			schemes = markdownConfig.AllowedURLSchemes
+		}
+		if len(markdownConfig.AllowedAttrs) > 0 {
+			attrs = markdownConfig.AllowedAttrs
+		}
+	}
+
+	policy.AllowElements(tags...)
+	for tag, tagAttrs := range attrs {
+		policy.AllowAttrs(tagAttrs...).OnElements(tag)
+	}
+	policy.AllowURLSchemes(schemes...)
+	policy.RequireNoFollowOnLinks(true)
+
+	// 代码块语法类名和标题锚点始终保留
+	policy.AllowAttrs("class").Matching(codeClassPattern).OnElements("code")
+	policy.AllowAttrs("id").Matching(headingIDPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+	sanitizePolicy = policy
+}
+
+// RenderMarkdown 将Markdown渲染为经过白名单过滤的HTML，并生成目录
+func RenderMarkdown(source string) (string, []dto.TOCItem, error) {
+	markdownOnce.Do(initMarkdown)
+
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := markdownRenderer.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := markdownRenderer.Renderer().Render(&buf, src, doc); err != nil {
+		return "", nil, fmt.Errorf("渲染Markdown失败: %v", err)
+	}
+
+	return sanitizePolicy.Sanitize(buf.String()), buildTOC(doc, src), nil
+}
+
+// buildTOC 遍历文档中的标题，按层级生成嵌套目录
+func buildTOC(doc ast.Node, src []byte) []dto.TOCItem {
+	root := &dto.TOCItem{}
+	// stack[i] 为当前路径上的目录节点，stack[0] 为虚拟根节点
+	stack := []*dto.TOCItem{root}
+
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		item := dto.TOCItem{
+			Level:    heading.Level,
+			Text:     nodeText(heading, src),
+			Children: []dto.TOCItem{},
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				item.ID = string(b)
+			}
+		}
+
+		// 回退到比当前标题层级更高的父节点
+		for len(stack) > 1 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, item)
+		stack = append(stack, &parent.Children[len(parent.Children)-1])
+		return ast.WalkSkipChildren, nil
+	})
+
+	if root.Children == nil {
+		return []dto.TOCItem{}
+	}
+	return root.Children
+}
+
+// nodeText 提取节点下的纯文本
+func nodeText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := child.(type) {
+		case *ast.Text:
+			sb.Write(t.Segment.Value(src))
+		case *ast.String:
+			sb.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// headingIDs 标题锚点ID生成器，中文标题转为拼音，重复时追加序号
+type headingIDs struct {
+	values map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{values: map[string]bool{}}
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for i := 1; h.values[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	h.values[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.values[string(value)] = true
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"go_test/dto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "基本语法",
+			source:   "**粗体** 和 *斜体*",
+			contains: []string{"<strong>粗体</strong>", "<em>斜体</em>"},
+		},
+		{
+			name:     "过滤脚本",
+			source:   "hello<script>alert(1)</script>",
+			contains: []string{"hello"},
+			excludes: []string{"<script", "alert(1)"},
+		},
+		{
+			name:     "过滤事件属性",
+			source:   `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			contains: []string{`src="https://example.com/a.png"`},
+			excludes: []string{"onerror"},
+		},
+		{
+			name:     "过滤javascript链接",
+			source:   "[点我](javascript:alert(1))",
+			excludes: []string{"javascript:"},
+		},
+		{
+			name:     "链接添加nofollow",
+			source:   "[官网](https://go.dev)",
+			contains: []string{`href="https://go.dev"`, `rel="nofollow"`},
+		},
+		{
+			name:     "保留代码块语言类名",
+			source:   "```go\nfmt.Println(1)\n```",
+			contains: []string{`<code class="language-go">`},
+		},
+		{
+			name:     "过滤非法类名",
+			source:   `<code class="evil">x</code>`,
+			excludes: []string{"evil"},
+		},
+		{
+			name:     "GFM表格",
+			source:   "| a | b |\n|---|---|\n| 1 | 2 |",
+			contains: []string{"<table>", "<td>1</td>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, _, err := RenderMarkdown(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(html, s) {
+					t.Errorf("html = %q, want contains %q", html, s)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(html, s) {
+					t.Errorf("html = %q, should not contain %q", html, s)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownTOC(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []dto.TOCItem
+	}{
+		{
+			name:   "没有标题",
+			source: "正文",
+			want:   []dto.TOCItem{},
+		},
+		{
+			name:   "按层级嵌套",
+			source: "# 简介\n## Install\n### Linux\n## Usage\n# 总结",
+			want: []dto.TOCItem{
+				{Level: 1, ID: "jian-jie", Text: "简介", Children: []dto.TOCItem{
+					{Level: 2, ID: "install", Text: "Install", Children: []dto.TOCItem{
+						{Level: 3, ID: "linux", Text: "Linux", Children: []dto.TOCItem{}},
+					}},
+					{Level: 2, ID: "usage", Text: "Usage", Children: []dto.TOCItem{}},
+				}},
+				{Level: 1, ID: "zong-jie", Text: "总结", Children: []dto.TOCItem{}},
+			},
+		},
+		{
+			name:   "跳级标题挂在最近的上级",
+			source: "## A\n#### B\n### C",
+			want: []dto.TOCItem{
+				{Level: 2, ID: "a", Text: "A", Children: []dto.TOCItem{
+					{Level: 4, ID: "b", Text: "B", Children: []dto.TOCItem{}},
+					{Level: 3, ID: "c", Text: "C", Children: []dto.TOCItem{}},
+				}},
+			},
+		},
+		{
+			name:   "重复标题追加序号",
+			source: "## FAQ\n## FAQ\n## !!!",
+			want: []dto.TOCItem{
+				{Level: 2, ID: "faq", Text: "FAQ", Children: []dto.TOCItem{}},
+				{Level: 2, ID: "faq-1", Text: "FAQ", Children: []dto.TOCItem{}},
+				{Level: 2, ID: "heading", Text: "!!!", Children: []dto.TOCItem{}},
+			},
+		},
+		{
+			name:   "标题中的格式只取文本",
+			source: "## 使用 `go test`",
+			want: []dto.TOCItem{
+				{Level: 2, ID: "shi-yong-go-test", Text: "使用 go test", Children: []dto.TOCItem{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, toc, err := RenderMarkdown(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(toc, tt.want) {
+				t.Errorf("toc = %+v, want %+v", toc, tt.want)
+			}
+		})
+	}
+}
+
+// 标题锚点ID保留在输出的HTML中，与目录一致
+func TestRenderMarkdownHeadingID(t *testing.T) {
+	html, _, err := RenderMarkdown("## 数据库")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, `<h2 id="shu-ju-ku">数据库</h2>`) {
+		t.Errorf("html = %q, want heading id", html)
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"go_test/global"
+	"time"
+)
+
+var rateLimitCtx = context.Background()
+
+// rateLimitScript 计数加一，第一次计数时设置窗口过期时间，在同一个脚本中执行，不会留下没有过期时间的计数
+const rateLimitScript = `
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`
+
+// AllowRequest 固定窗口限流：window内同一个key最多允许limit次，Redis不可用时放行
+func AllowRequest(scope, key string, limit int, window time.Duration) (bool, error) {
+	if limit <= 0 {
+		return true, nil
+	}
+	redisKey := fmt.Sprintf("%s:%s:%s", global.CacheKeyRateLimit, scope, key)
+	count, err := global.RedisDB.Eval(rateLimitCtx, rateLimitScript, []string{redisKey}, window.Milliseconds()).Int64()
+	if err != nil {
+		return true, err
+	}
+	return count <= int64(limit), nil
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"go_test/global"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestAllowRequest(t *testing.T) {
+	mr := miniredis.RunT(t)
+	global.RedisDB = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer global.RedisDB.Close()
+
+	for i := 1; i <= 4; i++ {
+		ok, err := AllowRequest("test", "key", 3, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i <= 3; ok != want {
+			t.Fatalf("request %d: allowed = %v, want %v", i, ok, want)
+		}
+	}
+
+	// 计数从第一次请求开始过期，窗口结束后重新计数
+	if ttl := mr.TTL(global.CacheKeyRateLimit + ":test:key"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("ttl = %v, want (0, 1m]", ttl)
+	}
+	mr.FastForward(time.Minute)
+	if ok, _ := AllowRequest("test", "key", 3, time.Minute); !ok {
+		t.Fatal("request after window should be allowed")
+	}
+}
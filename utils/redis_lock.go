@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"go_test/global"
+	"time"
+)
+
+// releaseLockScript 仅当锁仍由自己持有时才删除，避免误删其他实例的锁
+const releaseLockScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+// AcquireLock 基于SET NX EX获取分布式锁，成功时返回用于释放锁的token
+func AcquireLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	token := hex.EncodeToString(buf)
+
+	ok, err := global.RedisDB.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// ReleaseLock 释放分布式锁
+func ReleaseLock(ctx context.Context, key, token string) error {
+	return global.RedisDB.Eval(ctx, releaseLockScript, []string{key}, token).Err()
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go_test/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignedToken 签名令牌的内容，Nonce用于在数据库中登记实现一次性使用
+type SignedToken struct {
+	Purpose   string
+	UserID    uint
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+var errInvalidSignedToken = errors.New("链接无效或已过期")
+
+// SignToken 生成带HMAC-SHA256签名和过期时间的令牌，格式为 base64(内容).base64(签名)
+func SignToken(token SignedToken) (string, error) {
+	key, err := signedTokenKey()
+	if err != nil {
+		return "", err
+	}
+	return signToken(token, key), nil
+}
+
+// VerifySignedToken 校验签名、用途和过期时间；是否已使用由调用方检查
+func VerifySignedToken(raw, purpose string) (*SignedToken, error) {
+	key, err := signedTokenKey()
+	if err != nil {
+		return nil, err
+	}
+	return verifySignedToken(raw, purpose, key, time.Now())
+}
+
+func signToken(token SignedToken, key []byte) string {
+	payload := fmt.Sprintf("%s|%d|%d|%s", token.Purpose, token.UserID, token.ExpiresAt.Unix(), token.Nonce)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(signPayload(payload, key))
+}
+
+func verifySignedToken(raw, purpose string, key []byte, now time.Time) (*SignedToken, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 2 {
+		return nil, errInvalidSignedToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errInvalidSignedToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidSignedToken
+	}
+	if !hmac.Equal(sig, signPayload(string(payload), key)) {
+		return nil, errInvalidSignedToken
+	}
+
+	fields := strings.Split(string(payload), "|")
+	if len(fields) != 4 || fields[0] != purpose {
+		return nil, errInvalidSignedToken
+	}
+	userID, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return nil, errInvalidSignedToken
+	}
+	exp, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, errInvalidSignedToken
+	}
+	expiresAt := time.Unix(exp, 0)
+	if now.After(expiresAt) {
+		return nil, errInvalidSignedToken
+	}
+
+	return &SignedToken{Purpose: fields[0], UserID: uint(userID), Nonce: fields[3], ExpiresAt: expiresAt}, nil
+}
+
+// signedTokenKey 签名密钥，由JWT密钥派生
+func signedTokenKey() ([]byte, error) {
+	jwtConfig := config.GetJWTConfig()
+	if jwtConfig == nil {
+		return nil, errors.New("JWT配置未初始化")
+	}
+	return []byte("signed-token:" + jwtConfig.Secret), nil
+}
+
+func signPayload(payload string, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
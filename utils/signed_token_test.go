@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignedToken(t *testing.T) {
+	key := []byte("signed-token:test")
+	now := time.Unix(1700000000, 0)
+	valid := SignedToken{Purpose: "verify_email", UserID: 42, Nonce: "abc", ExpiresAt: now.Add(time.Hour)}
+	raw := signToken(valid, key)
+	payload, sig, _ := strings.Cut(raw, ".")
+
+	tests := []struct {
+		name    string
+		raw     string
+		purpose string
+		key     []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{"有效令牌", raw, "verify_email", key, now, false},
+		{"到期时刻仍有效", raw, "verify_email", key, valid.ExpiresAt, false},
+		{"已过期", raw, "verify_email", key, valid.ExpiresAt.Add(time.Second), true},
+		{"用途不符", raw, "reset_password", key, now, true},
+		{"密钥不同", raw, "verify_email", []byte("signed-token:other"), now, true},
+		{"篡改内容", base64.RawURLEncoding.EncodeToString([]byte("verify_email|1|9999999999|abc")) + "." + sig, "verify_email", key, now, true},
+		{"篡改签名", payload + "." + base64.RawURLEncoding.EncodeToString([]byte("bad")), "verify_email", key, now, true},
+		{"缺少签名", payload, "verify_email", key, now, true},
+		{"不是base64", "!!!.???", "verify_email", key, now, true},
+		{"空令牌", "", "verify_email", key, now, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifySignedToken(tt.raw, tt.purpose, tt.key, tt.now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("verifySignedToken() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != valid {
+				t.Errorf("verifySignedToken() = %+v, want %+v", *got, valid)
+			}
+		})
+	}
+}
+
+// 用途和随机数中的分隔符会导致字段错位，签名正确也应拒绝
+func TestVerifySignedTokenMalformedPayload(t *testing.T) {
+	key := []byte("signed-token:test")
+	now := time.Unix(1700000000, 0)
+	raw := signToken(SignedToken{Purpose: "verify_email", UserID: 1, Nonce: "a|b", ExpiresAt: now.Add(time.Hour)}, key)
+	if _, err := verifySignedToken(raw, "verify_email", key, now); err == nil {
+		t.Error("payload with extra fields should be rejected")
+	}
+}
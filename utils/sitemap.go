@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// SitemapMaxURLs 单个站点地图文件最多包含的URL数量（sitemaps.org协议限制）
+const SitemapMaxURLs = 50000
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// SitemapURL 站点地图条目，LastMod为零值时不输出
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name          `xml:"urlset"`
+	XMLNS   string            `xml:"xmlns,attr"`
+	URLs    []sitemapLocEntry `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name          `xml:"sitemapindex"`
+	XMLNS    string            `xml:"xmlns,attr"`
+	Sitemaps []sitemapLocEntry `xml:"sitemap"`
+}
+
+type sitemapLocEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// RenderSitemap 渲染站点地图（urlset）
+func RenderSitemap(urls []SitemapURL) ([]byte, error) {
+	return marshalXML(sitemapURLSet{XMLNS: sitemapNS, URLs: sitemapEntries(urls)})
+}
+
+// RenderSitemapIndex 渲染站点地图索引（sitemapindex）
+func RenderSitemapIndex(sitemaps []SitemapURL) ([]byte, error) {
+	return marshalXML(sitemapIndex{XMLNS: sitemapNS, Sitemaps: sitemapEntries(sitemaps)})
+}
+
+func sitemapEntries(urls []SitemapURL) []sitemapLocEntry {
+	entries := make([]sitemapLocEntry, 0, len(urls))
+	for _, u := range urls {
+		entry := sitemapLocEntry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.Format(time.RFC3339)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderSitemap(t *testing.T) {
+	lastMod := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		render   func([]SitemapURL) ([]byte, error)
+		urls     []SitemapURL
+		contains []string
+		excludes []string
+	}{
+		{
+			name:   "urlset",
+			render: RenderSitemap,
+			urls: []SitemapURL{
+				{Loc: "https://example.com/"},
+				{Loc: "https://example.com/articles/a?x=1&y=2", LastMod: lastMod},
+			},
+			contains: []string{
+				`<?xml version="1.0" encoding="UTF-8"?>`,
+				`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+				"<url>\n    <loc>https://example.com/</loc>\n  </url>",
+				"<loc>https://example.com/articles/a?x=1&amp;y=2</loc>",
+				"<lastmod>2024-05-01T08:00:00Z</lastmod>",
+			},
+		},
+		{
+			name:   "没有条目",
+			render: RenderSitemap,
+			urls:   nil,
+			contains: []string{
+				`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></urlset>`,
+			},
+			excludes: []string{"<url>"},
+		},
+		{
+			name:   "sitemapindex",
+			render: RenderSitemapIndex,
+			urls: []SitemapURL{
+				{Loc: "https://example.com/sitemaps/pages-1.xml", LastMod: lastMod},
+				{Loc: "https://example.com/sitemaps/articles-1.xml"},
+			},
+			contains: []string{
+				`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+				"<sitemap>\n    <loc>https://example.com/sitemaps/pages-1.xml</loc>\n    <lastmod>2024-05-01T08:00:00Z</lastmod>",
+				"<loc>https://example.com/sitemaps/articles-1.xml</loc>",
+			},
+			excludes: []string{"<url>"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.render(tt.urls)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(string(body), s) {
+					t.Errorf("output missing %q:\n%s", s, body)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(string(body), s) {
+					t.Errorf("output should not contain %q:\n%s", s, body)
+				}
+			}
+		})
+	}
+}
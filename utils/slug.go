@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// MaxSlugLength slug最大长度（不含冲突后缀）
+const MaxSlugLength = 80
+
+var pinyinArgs = pinyin.NewArgs() // 默认不带声调
+
+// Slugify 将标题转换为URL友好的slug：英文数字转小写，汉字转拼音，其余字符作为分隔符
+// 无法转换出任何字符时返回空字符串，由调用方回退到短哈希
+func Slugify(title string) string {
+	words := make([]string, 0)
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range title {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			current.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Han, r):
+			// 每个汉字的拼音作为独立的词
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 && py[0] != "" {
+				words = append(words, py[0])
+			}
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	slug := strings.Join(words, "-")
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	return slug
+}
+
+// ShortHash 返回文本的8位十六进制短哈希，用作slug的兜底值
+func ShortHash(text string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(text)))[:8]
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"英文转小写", "Hello World", "hello-world"},
+		{"标点作为分隔符", "Go 1.24: What's New?", "go-1-24-what-s-new"},
+		{"合并连续分隔符", "  a -- b  ", "a-b"},
+		{"汉字转拼音", "数据库", "shu-ju-ku"},
+		{"中英混排", "Go语言入门", "go-yu-yan-ru-men"},
+		{"无法转换时返回空", "!!! ???", ""},
+		{"非ASCII字母作为分隔符", "café", "caf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyMaxLength(t *testing.T) {
+	title := strings.Repeat("abcdefghi ", 20)
+	got := Slugify(title)
+	if len(got) > MaxSlugLength {
+		t.Fatalf("len = %d, want <= %d", len(got), MaxSlugLength)
+	}
+	// 截断后不以分隔符结尾
+	if strings.HasSuffix(got, "-") {
+		t.Errorf("Slugify() = %q, should not end with '-'", got)
+	}
+}
+
+func TestShortHash(t *testing.T) {
+	got := ShortHash("hello")
+	if got != "5d41402a" {
+		t.Errorf("ShortHash() = %q, want %q", got, "5d41402a")
+	}
+	if ShortHash("hello") != got {
+		t.Error("ShortHash should be deterministic")
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"go_test/global"
+	"time"
+)
+
+var tokenCtxRedis = context.Background()
+
+// RandomToken 生成n字节随机数的URL安全字符串
+func RandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken 计算令牌的SHA-256摘要，数据库中只保存摘要
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// DenyAccessToken 将访问令牌加入黑名单，保留到令牌过期
+func DenyAccessToken(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	return global.RedisDB.Set(tokenCtxRedis, fmt.Sprintf("%s:%s", global.CacheKeyTokenDenylist, jti), 1, ttl).Err()
+}
+
+// DenySession 将登录会话加入黑名单，会话下签发的访问令牌全部失效，保留到expiresAt
+func DenySession(sessionID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if sessionID == "" || ttl <= 0 {
+		return nil
+	}
+	return global.RedisDB.Set(tokenCtxRedis, fmt.Sprintf("%s:%s", global.CacheKeySessionDenylist, sessionID), 1, ttl).Err()
+}
+
+// IsTokenRevoked 判断访问令牌本身或其所属会话是否已被注销
+func IsTokenRevoked(jti, sessionID string) (bool, error) {
+	count, err := global.RedisDB.Exists(tokenCtxRedis,
+		fmt.Sprintf("%s:%s", global.CacheKeyTokenDenylist, jti),
+		fmt.Sprintf("%s:%s", global.CacheKeySessionDenylist, sessionID),
+	).Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ShouldTouchSession 会话活跃时间写入节流，interval内只有第一次调用返回true
+func ShouldTouchSession(sessionID string, interval time.Duration) (bool, error) {
+	return global.RedisDB.SetNX(tokenCtxRedis, fmt.Sprintf("%s:%s", global.CacheKeySessionSeen, sessionID), 1, interval).Result()
+}
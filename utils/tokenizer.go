@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token 分词结果，Position为词在文本中的序号，用于短语匹配
+type Token struct {
+	Text     string
+	Position int
+}
+
+// Tokenize 对文本分词：连续的字母数字作为一个词（转小写），中日韩文字按二元组切分
+// 例如 "Go语言数据库" -> go, 语言, 言数, 数据, 据库
+func Tokenize(text string) []Token {
+	return tokenize(text, false)
+}
+
+// TokenizeForIndex 建立索引用的分词：在Tokenize的基础上，两字以上的中日韩文字串额外输出每个单字，
+// 单字与所在的二元组共用位置（最后一个字与最后一个二元组共用），不影响短语匹配，
+// 使单字查询（如"库"）也能命中"数据库"
+func TokenizeForIndex(text string) []Token {
+	return tokenize(text, true)
+}
+
+func tokenize(text string, unigrams bool) []Token {
+	tokens := make([]Token, 0)
+	position := 0
+	var word []rune
+	var cjk []rune
+
+	emit := func(text string, pos int) {
+		tokens = append(tokens, Token{Text: text, Position: pos})
+	}
+	flushWord := func() {
+		if len(word) > 0 {
+			emit(string(word), position)
+			position++
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			emit(string(cjk), position)
+			position++
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			emit(string(cjk[i:i+2]), position)
+			if unigrams {
+				emit(string(cjk[i]), position)
+				if i+2 == len(cjk) {
+					emit(string(cjk[i+1]), position)
+				}
+			}
+			position++
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return tokens
+}
+
+// ParseSearchQuery 解析搜索关键词，双引号内的内容作为一个短语，其余按空白切分
+// 会去掉MySQL布尔全文检索的运算符，返回的每一项都按短语匹配
+func ParseSearchQuery(query string) []string {
+	terms := make([]string, 0)
+	parts := strings.Split(query, `"`)
+	for i, part := range parts {
+		// 奇数下标位于一对引号之间
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(sanitizeSearchTerm(part)), " "); phrase != "" {
+				terms = append(terms, phrase)
+			}
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			if term := sanitizeSearchTerm(field); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// sanitizeSearchTerm 去掉搜索词中的布尔检索运算符
+func sanitizeSearchTerm(term string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case '+', '-', '<', '>', '(', ')', '~', '*', '@', '"':
+			return ' '
+		}
+		return r
+	}, term))
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{"空文本", "", []Token{}},
+		{"英文转小写", "Hello, World", []Token{{"hello", 0}, {"world", 1}}},
+		{"中文按二元组切分", "Go语言数据库", []Token{{"go", 0}, {"语言", 1}, {"言数", 2}, {"数据", 3}, {"据库", 4}}},
+		{"单个汉字", "库 a", []Token{{"库", 0}, {"a", 1}}},
+		{"数字和字母相连", "mysql8 v1.2", []Token{{"mysql8", 0}, {"v1", 1}, {"2", 2}}},
+		{"日文假名", "カタカナ", []Token{{"カタ", 0}, {"タカ", 1}, {"カナ", 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// 索引分词额外输出单字，与所在二元组共用位置，二元组的位置与Tokenize一致
+func TestTokenizeForIndex(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{"单个汉字", "库", []Token{{"库", 0}}},
+		{"两个汉字", "语言", []Token{{"语言", 0}, {"语", 0}, {"言", 0}}},
+		{
+			"中英混排",
+			"Go数据库 a",
+			[]Token{{"go", 0}, {"数据", 1}, {"数", 1}, {"据库", 2}, {"据", 2}, {"库", 2}, {"a", 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TokenizeForIndex(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TokenizeForIndex(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"空白分隔", "  go  mysql ", []string{"go", "mysql"}},
+		{"引号内为短语", `go "full  text" search`, []string{"go", "full text", "search"}},
+		{"去掉布尔运算符", "+go -java mysql*", []string{"go", "java", "mysql"}},
+		{"未闭合的引号", `"hello world`, []string{"hello world"}},
+		{"只有运算符", `+ - "" ()`, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSearchQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238附录B中SHA1测试用的密钥 "12345678901234567890"
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 6238附录B的测试向量，取8位验证码的后6位
+func TestTOTPCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(time.Unix(tt.unix, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("TOTPCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTOTPCodeSecretFormat(t *testing.T) {
+	want, _ := TOTPCode(rfc6238Secret, 1)
+	// 兼容小写和带填充的密钥
+	if got, err := TOTPCode(strings.ToLower(rfc6238Secret)+"====", 1); err != nil || got != want {
+		t.Errorf("TOTPCode(lower) = (%q, %v), want %q", got, err, want)
+	}
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Error("invalid secret should return error")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := TOTPStep(now)
+	code := func(s int64) string {
+		c, _ := TOTPCode(rfc6238Secret, s)
+		return c
+	}
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		ok       bool
+	}{
+		{"当前时间步", code(step), step, true},
+		{"允许前一个时间步", code(step - 1), step - 1, true},
+		{"允许后一个时间步", code(step + 1), step + 1, true},
+		{"超出允许的偏差", code(step - 2), 0, false},
+		{"忽略空格", code(step)[:3] + " " + code(step)[3:], step, true},
+		{"位数不对", "12345", 0, false},
+		{"错误的验证码", "000000", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := ValidateTOTP(rfc6238Secret, tt.code, now)
+			if ok != tt.ok || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q) = (%d, %v), want (%d, %v)", tt.code, gotStep, ok, tt.wantStep, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("My Blog", "alice@example.com", "ABC"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/My Blog:alice@example.com" {
+		t.Errorf("uri = %s", uri)
+	}
+	query := uri.Query()
+	for key, want := range map[string]string{"secret": "ABC", "issuer": "My Blog", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Errorf("generated secret should be valid: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("len(secret) = %d, want 32", len(secret))
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import "strings"
+
+// uaRule User-Agent关键字与名称的对应关系，按顺序匹配
+type uaRule struct {
+	keyword string
+	name    string
+}
+
+// 浏览器规则，Edge、Opera等基于Chromium的浏览器需排在Chrome之前
+var browserRules = []uaRule{
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"micromessenger", "微信"},
+	{"firefox/", "Firefox"},
+	{"chrome/", "Chrome"},
+	{"crios/", "Chrome"},
+	{"safari/", "Safari"},
+	{"curl/", "curl"},
+	{"postman", "Postman"},
+	{"okhttp", "OkHttp"},
+	{"go-http-client", "Go"},
+	{"python-requests", "Python"},
+}
+
+// 系统规则，iOS和Android的UA中也包含Mac/Linux字样，需排在前面
+var osRules = []uaRule{
+	{"iphone", "iPhone"},
+	{"ipad", "iPad"},
+	{"android", "Android"},
+	{"windows", "Windows"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"cros", "ChromeOS"},
+	{"linux", "Linux"},
+}
+
+// DeviceLabel 根据User-Agent生成便于识别的设备名称，如 "Chrome on Windows"
+func DeviceLabel(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	browser := matchUARule(ua, browserRules)
+	os := matchUARule(ua, osRules)
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "未知设备"
+	}
+}
+
+func matchUARule(ua string, rules []uaRule) string {
+	for _, rule := range rules {
+		if strings.Contains(ua, rule.keyword) {
+			return rule.name
+		}
+	}
+	return ""
+}